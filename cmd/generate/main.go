@@ -1,73 +1,536 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
+	"github.com/thomasoddsund/hockeykalender/internal/api"
+	"github.com/thomasoddsund/hockeykalender/internal/cache"
+	"github.com/thomasoddsund/hockeykalender/internal/diff"
 	"github.com/thomasoddsund/hockeykalender/internal/ehl"
+	"github.com/thomasoddsund/hockeykalender/internal/export"
+	"github.com/thomasoddsund/hockeykalender/internal/ical"
 	"github.com/thomasoddsund/hockeykalender/internal/output"
+	"github.com/thomasoddsund/hockeykalender/internal/standings"
+	"github.com/thomasoddsund/hockeykalender/internal/state"
+	"github.com/thomasoddsund/hockeykalender/internal/watch"
 )
 
+// exitChanged is the exit code of a successful run that found schedule changes.
+// It differs from 2, which Go uses for invalid flags and panics.
+const exitChanged = 3
+
+// seriesFlag collects repeated -series flags
+type seriesFlag []ehl.Series
+
+func (f *seriesFlag) String() string {
+	slugs := make([]string, len(*f))
+	for i, s := range *f {
+		slugs[i] = s.Slug
+	}
+	return strings.Join(slugs, ",")
+}
+
+func (f *seriesFlag) Set(value string) error {
+	series, err := ehl.ParseSeries(value)
+	if err != nil {
+		return err
+	}
+	*f = append(*f, series)
+	return nil
+}
+
+// config holds the command line options shared by all series
+type config struct {
+	outputDir   string
+	cacheDir    string
+	stateDir    string
+	season      string
+	overlapDays int
+	graceDays   int
+	feedDays    int
+	archive     bool
+	accept      bool // Remove missing games even when most of a season is missing
+	presets     [][]ical.Alarm
+	feeds       []output.FeedSpec
+	loc         *time.Location // For the change summary
+	opts        []ical.Option
+	policy      watch.Policy       // Poll intervals of -watch
+	client      []ehl.ClientOption // Timeout and retries of API requests
+}
+
+// seriesRun is the outcome of generating a series, where -watch picks up
+type seriesRun struct {
+	season  ehl.Season
+	seasons []ehl.SeasonGames // Published games, the current season last
+	catalog []ehl.SeasonGames // Complete games of every season, for the JSON API
+	changes []diff.Change
+}
+
 func main() {
-	outputDir := flag.String("output", "dist", "Output directory for generated files")
+	var cfg config
+	flag.StringVar(&cfg.outputDir, "output", "dist", "Output directory for generated files")
+	flag.StringVar(&cfg.cacheDir, "cache", ".cache", "Directory for cached games of completed seasons")
+	flag.StringVar(&cfg.stateDir, "state", ".cache/state", "Directory for per-series state kept between runs (DTSTAMP, SEQUENCE)")
+	flag.StringVar(&cfg.season, "season", "", "Season to generate by name (e.g. 2025/2026) or UUID (default: chosen from game dates)")
+	flag.IntVar(&cfg.overlapDays, "overlap-days", 60, "Keep the previous season's games from the last N days in the current feeds (0 to disable)")
+	flag.IntVar(&cfg.graceDays, "cancelled-grace-days", 14, "Keep cancelled, postponed and removed games as cancelled events for N days")
+	flag.IntVar(&cfg.feedDays, "feed-days", 30, "Keep schedule changes in the Atom feeds for N days")
+	flag.BoolVar(&cfg.archive, "archive", false, "Also generate archive calendars for every season, e.g. dist/2024-2025/")
+	flag.BoolVar(&cfg.accept, "accept-removals", false, "Remove games missing from the schedule even when most of a season is missing, e.g. after the schedule was reissued")
+	alarmPresets := flag.String("alarm-presets", "", "Comma-separated alarm sets to generate, e.g. none,1h,2h,1d-30m (default: all combinations of 1d, 3h, 1h, 15m)")
+	feeds := flag.String("feeds", "", "Comma-separated extra feeds, e.g. valerenga+storhamar,valerenga-vs-storhamar")
+	timeZone := flag.String("timezone", ical.DefaultTimeZone, "Time zone for event times, empty for UTC")
+	rootSeries := flag.String("root-series", ehl.DefaultSeries.Slug, "Series whose feeds are also written to the output root (empty to disable)")
+	links := ical.DefaultLinks
+	flag.StringVar(&links.Game, "game-url", links.Game, "URL template of the game page linked from events, {game} is the game UUID (empty to disable)")
+	flag.StringVar(&links.Venue, "venue-url", links.Venue, "URL template of the venue map linked from events, {venue} is the venue name (empty to disable)")
+	watchMode := flag.Bool("watch", false, "Keep running after generating, polling for live scores and schedule changes until stopped")
+	cfg.policy = watch.DefaultPolicy()
+	flag.DurationVar(&cfg.policy.Live, "live-interval", cfg.policy.Live, "With -watch, how often to poll while a game is live")
+	flag.DurationVar(&cfg.policy.Idle, "idle-interval", cfg.policy.Idle, "With -watch, how often to poll on days without games")
+	timeout := flag.Duration("timeout", ehl.DefaultTimeout, "Timeout of each request to the EHL API (0 for none)")
+	retry := ehl.DefaultRetryPolicy()
+	flag.IntVar(&retry.Retries, "retries", retry.Retries, "Retries of failed requests to the EHL API, with exponential backoff")
+	var seriesList seriesFlag
+	flag.Var(&seriesList, "series", "Series to generate as slug=uuid[:name] (repeatable, default EHL)")
 	flag.Parse()
 
-	log.Println("Starting EHL calendar generation...")
+	if len(seriesList) == 0 {
+		seriesList = seriesFlag{ehl.DefaultSeries}
+	}
+
+	cfg.presets = ical.DefaultAlarmPresets()
+	if *alarmPresets != "" {
+		presets, err := ical.ParseAlarmPresets(*alarmPresets)
+		if err != nil {
+			log.Fatalf("Invalid -alarm-presets: %v", err)
+		}
+		cfg.presets = presets
+	}
+
+	if *feeds != "" {
+		specs, err := output.ParseFeedSpecs(*feeds)
+		if err != nil {
+			log.Fatalf("Invalid -feeds: %v", err)
+		}
+		cfg.feeds = specs
+	}
+
+	if err := links.Validate(); err != nil {
+		log.Fatalf("Invalid link template: %v", err)
+	}
+
+	if *timeout < 0 || retry.Retries < 0 {
+		log.Fatalf("Invalid -timeout or -retries: must not be negative")
+	}
+	cfg.client = []ehl.ClientOption{ehl.WithTimeout(*timeout), ehl.WithRetryPolicy(retry)}
+
+	cfg.opts = []ical.Option{ical.WithSeasonContext(), ical.WithLinks(links), ical.WithVenues(ehl.DefaultVenues)}
+	cfg.loc = time.UTC
+	if *timeZone != "" {
+		loc, err := time.LoadLocation(*timeZone)
+		if err != nil {
+			log.Fatalf("Invalid -timezone: %v", err)
+		}
+		cfg.loc = loc
+		cfg.opts = append(cfg.opts, ical.WithTimeZone(loc))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Println("Starting calendar generation...")
+
+	changed := false
+	runs := make([]seriesRun, len(seriesList))
+	dirs := make([][]string, len(seriesList))
+	for i, series := range seriesList {
+		dirs[i] = []string{filepath.Join(cfg.outputDir, series.Slug)}
+		if series.Slug == *rootSeries {
+			// Keep the original dist/{team}.ics URLs working for existing subscribers
+			dirs[i] = append(dirs[i], cfg.outputDir)
+		}
+
+		run, err := generateSeries(ctx, cfg, series, dirs[i])
+		if err != nil {
+			log.Fatalf("Failed to generate %s: %v", series.Name, err)
+		}
+		runs[i] = run
+		changed = changed || len(run.changes) > 0
+	}
+
+	// Copy web files to output
+	log.Println("Copying web files...")
+	if err := copyWebFiles(cfg.outputDir); err != nil {
+		log.Fatalf("Failed to copy web files: %v", err)
+	}
+
+	if *watchMode {
+		var wg sync.WaitGroup
+		for i, series := range seriesList {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				watchSeries(ctx, cfg, series, dirs[i], runs[i])
+			}()
+		}
+		wg.Wait()
+
+		log.Println("Stopped watching")
+		return
+	}
+
+	log.Println("Done!")
+
+	if changed {
+		os.Exit(exitChanged)
+	}
+}
+
+// generateSeries fetches the current season of a series and writes its calendars to each of dirs.
+// It returns the published games and the schedule changes since the previous run.
+func generateSeries(ctx context.Context, cfg config, series ehl.Series, dirs []string) (seriesRun, error) {
+	log.Printf("Generating %s (%s)...", series.Name, series.UUID)
 
 	// Create API client
-	client := ehl.NewDefaultClient()
+	client := ehl.NewSeriesClient(ehl.DefaultBaseURL, series.UUID, cfg.client...)
+	store := cache.New(cfg.cacheDir)
 
-	// Get current season
-	log.Println("Fetching current season...")
-	season, err := client.GetCurrentSeason()
+	season, games, err := fetchSeason(ctx, client, cfg.season)
 	if err != nil {
-		log.Fatalf("Failed to get current season: %v", err)
+		return seriesRun{}, err
+	}
+	log.Printf("Found %d games", len(games))
+	if err := ehl.CheckDecisions(games); err != nil {
+		log.Printf("Warning: %s: %v, overtime and shootout results may be missing", season.Name, err)
 	}
-	log.Printf("Current season: %s (UUID: %s)", season.Name, season.UUID)
 
-	// Fetch games
-	log.Println("Fetching games...")
-	games, err := client.FetchGames(season.UUID)
+	// Track game changes between runs so unchanged events render identically,
+	// and removed or cancelled games are announced before they disappear
+	statePath := filepath.Join(cfg.stateDir, series.Slug+".json")
+	st, err := state.Load(statePath)
 	if err != nil {
-		log.Fatalf("Failed to fetch games: %v", err)
+		return seriesRun{}, err
 	}
-	log.Printf("Found %d games", len(games))
+	st.AcceptRemovals = cfg.accept
+	now := time.Now()
+	grace := time.Duration(cfg.graceDays) * 24 * time.Hour
 
-	// Extract teams
-	teams := ehl.ExtractTeams(games)
-	sort.Slice(teams, func(i, j int) bool {
-		return teams[i].ShortName < teams[j].ShortName
-	})
+	// Compare with the games recorded by the previous run before the state moves on
+	if !st.HasSeason(season.UUID) {
+		log.Printf("No recorded games for %s, not reporting changes", season.Name)
+	}
+	changes := st.Compare(season.UUID, games)
+	seasons := []ehl.SeasonGames{{Season: season, Games: updateState(st, season, games, now, grace)}}
+
+	// Bridge the season rollover with the tail of the previous season, unless rebuilding a fixed season
+	if cfg.season == "" && cfg.overlapDays > 0 {
+		previous, ok, err := fetchPrevious(ctx, client, store, series, season)
+		if err != nil {
+			return seriesRun{}, fmt.Errorf("failed to fetch previous season: %w", err)
+		}
+		if ok {
+			changes = append(st.Compare(previous.Season.UUID, previous.Games), changes...)
+			published := updateState(st, previous.Season, previous.Games, now, grace)
+			if tail := ehl.GamesSince(published, now.AddDate(0, 0, -cfg.overlapDays)); len(tail) > 0 {
+				log.Printf("Including %d games from %s", len(tail), previous.Season.Name)
+				seasons = append([]ehl.SeasonGames{{Season: previous.Season, Games: tail}}, seasons...)
+			}
+		}
+	}
+
+	// The JSON API and the archive describe every season in full
+	catalog, err := fetchCatalog(ctx, client, store, series, season, games)
+	if err != nil {
+		return seriesRun{}, fmt.Errorf("failed to fetch seasons: %w", err)
+	}
+
+	allGames := ehl.MergeSeasonGames(seasons)
+	teams := sortedTeams(allGames)
 	log.Printf("Found %d teams", len(teams))
 
 	for _, team := range teams {
 		log.Printf("  - %s (%s)", team.ShortName, team.Slug())
 	}
 
+	venues := ehl.ExtractVenues(allGames)
+	log.Printf("Found %d venues", len(venues))
+	for _, venue := range ehl.DefaultVenues.Unknown(venues) {
+		entry, _ := json.Marshal(map[string]ehl.VenueDetails{venue.UUID: {Name: venue.Name}})
+		log.Printf("  Unknown venue %q: add it to internal/ehl/data/venues.json with its address: %s", venue.Name, entry)
+	}
+
+	st.Changes = st.Changes.Add(changes, now, now.AddDate(0, 0, -cfg.feedDays))
+
+	feeds := seriesFeeds(cfg.feeds, teams)
+
 	// Generate calendars
-	log.Printf("Generating calendars to %s...", *outputDir)
-	stats, err := output.GenerateAllCalendars(*outputDir, games, teams, season.Name)
-	if err != nil {
-		log.Fatalf("Failed to generate calendars: %v", err)
+	st.UpdateDescriptions(ical.Descriptions(seasons, cfg.opts...), now)
+	opts := append(cfg.opts[:len(cfg.opts):len(cfg.opts)], ical.WithRevisions(st.Revisions()))
+	for _, dir := range dirs {
+		log.Printf("Generating calendars to %s...", dir)
+		stats, err := output.GenerateAllCalendars(dir, series, seasons, teams, cfg.presets, opts...)
+		if err != nil {
+			return seriesRun{}, fmt.Errorf("failed to generate calendars: %w", err)
+		}
+
+		log.Printf("Generated %d files (%.2f KB total)", stats.FilesWritten, float64(stats.TotalBytes)/1024)
+
+		venueStats, err := output.GenerateVenueCalendars(dir, series, seasons, venues, cfg.presets, opts...)
+		if err != nil {
+			return seriesRun{}, fmt.Errorf("failed to generate venue calendars: %w", err)
+		}
+		log.Printf("Generated %d venue calendar files", venueStats.FilesWritten)
+
+		if len(feeds) > 0 {
+			stats, err := output.GenerateCustomCalendars(dir, series, seasons, teams, feeds, cfg.presets, opts...)
+			if err != nil {
+				return seriesRun{}, fmt.Errorf("failed to generate custom feeds: %w", err)
+			}
+			log.Printf("Generated %d custom feed files", stats.FilesWritten)
+		}
+
+		if err := writeChanges(dir, changes, cfg.loc, now); err != nil {
+			return seriesRun{}, fmt.Errorf("failed to write change report: %w", err)
+		}
+
+		feedStats, err := output.GenerateAllFeeds(dir, series, teams, st.Changes, cfg.loc, now)
+		if err != nil {
+			return seriesRun{}, fmt.Errorf("failed to generate feeds: %w", err)
+		}
+		log.Printf("Generated %d change feeds", feedStats.FilesWritten)
+
+		apiFiles, err := api.WriteAll(filepath.Join(dir, "api", api.Version), catalog, seasons, teams)
+		if err != nil {
+			return seriesRun{}, fmt.Errorf("failed to write JSON API: %w", err)
+		}
+		log.Printf("Generated %d JSON API files", apiFiles)
+
+		exportFiles, err := export.WriteAll(dir, series.Slug, allGames, teams, cfg.loc)
+		if err != nil {
+			return seriesRun{}, fmt.Errorf("failed to write exports: %w", err)
+		}
+		log.Printf("Generated %d spreadsheet exports", exportFiles)
+
+		if err := writeStandings(dir, series, seasons[len(seasons)-1], now, cfg.loc); err != nil {
+			return seriesRun{}, fmt.Errorf("failed to write standings: %w", err)
+		}
 	}
+	log.Printf("Found %d schedule changes", len(changes))
 
-	log.Printf("Generated %d files (%.2f KB total)", stats.FilesWritten, float64(stats.TotalBytes)/1024)
+	if cfg.archive {
+		if err := generateArchive(st, series, catalog, dirs, cfg, now); err != nil {
+			return seriesRun{}, fmt.Errorf("failed to generate archive: %w", err)
+		}
+	}
 
-	// Copy web files to output
-	log.Println("Copying web files...")
-	if err := copyWebFiles(*outputDir); err != nil {
-		log.Fatalf("Failed to copy web files: %v", err)
+	if err := st.Save(statePath); err != nil {
+		return seriesRun{}, fmt.Errorf("failed to save state: %w", err)
 	}
 
 	// Generate team list for HTML page
-	generateTeamList(teams)
+	generateTeamList(series, teams)
 
-	log.Println("Done!")
+	return seriesRun{season: season, seasons: seasons, catalog: catalog, changes: changes}, nil
+}
+
+// seriesFeeds returns the custom feeds that apply to a series, those whose teams all play in it
+func seriesFeeds(specs []output.FeedSpec, teams []ehl.Team) []output.FeedSpec {
+	var feeds []output.FeedSpec
+	for _, spec := range specs {
+		if _, err := spec.Filter(output.TeamsBySlug(teams)); err != nil {
+			log.Printf("Skipping feed %s: %v", spec.Slug(), err)
+			continue
+		}
+		feeds = append(feeds, spec)
+	}
+	return feeds
+}
+
+// fetchSeason returns the requested season and its games, or the current season if none is requested
+func fetchSeason(ctx context.Context, client *ehl.Client, nameOrUUID string) (ehl.Season, []ehl.Game, error) {
+	if nameOrUUID == "" {
+		log.Println("Fetching current season...")
+		season, games, err := client.CurrentSeason(ctx, time.Now())
+		if err != nil {
+			return season, nil, fmt.Errorf("failed to get current season: %w", err)
+		}
+		log.Printf("Current season: %s (UUID: %s)", season.Name, season.UUID)
+		return season, games, nil
+	}
+
+	season, err := client.FindSeason(ctx, nameOrUUID)
+	if err != nil {
+		return season, nil, err
+	}
+	log.Printf("Season: %s (UUID: %s)", season.Name, season.UUID)
+
+	log.Println("Fetching games...")
+	games, err := client.FetchGames(ctx, season.UUID)
+	if err != nil {
+		return season, nil, fmt.Errorf("failed to fetch games: %w", err)
+	}
+
+	return season, games, nil
+}
+
+// fetchPrevious returns the season before current with all its games, and false if there is none
+func fetchPrevious(ctx context.Context, client *ehl.Client, store *cache.Store, series ehl.Series, current ehl.Season) (ehl.SeasonGames, bool, error) {
+	previous, ok, err := client.PreviousSeason(ctx, current)
+	if err != nil || !ok {
+		return ehl.SeasonGames{}, false, err
+	}
+
+	games, err := fetchSeasonGames(ctx, client, store, series, previous)
+	if err != nil {
+		return ehl.SeasonGames{}, false, err
+	}
+
+	return ehl.SeasonGames{Season: previous, Games: games}, true, nil
+}
+
+// fetchCatalog returns the complete games of every season of a series, oldest first,
+// reusing the already fetched games of the current season
+func fetchCatalog(ctx context.Context, client *ehl.Client, store *cache.Store, series ehl.Series, current ehl.Season, currentGames []ehl.Game) ([]ehl.SeasonGames, error) {
+	log.Println("Fetching all seasons...")
+	seasons, err := client.FetchSeasons(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(seasons, func(a, b ehl.Season) int { return strings.Compare(a.Name, b.Name) })
+
+	catalog := make([]ehl.SeasonGames, 0, len(seasons))
+	for _, season := range seasons {
+		games := currentGames
+		if season.UUID != current.UUID {
+			games, err = fetchSeasonGames(ctx, client, store, series, season)
+			if err != nil {
+				return nil, fmt.Errorf("season %s: %w", season.Name, err)
+			}
+		}
+		catalog = append(catalog, ehl.SeasonGames{Season: season, Games: games})
+	}
+	return catalog, nil
+}
+
+// generateArchive writes the full feed set of every season in catalog to {dir}/{season-slug}/
+func generateArchive(st *state.State, series ehl.Series, catalog []ehl.SeasonGames, dirs []string, cfg config, now time.Time) error {
+	for _, sg := range catalog {
+		season, games := sg.Season, sg.Games
+		if len(games) == 0 {
+			log.Printf("Skipping season %s: no games", season.Name)
+			continue
+		}
+
+		games = updateState(st, season, games, now, time.Duration(cfg.graceDays)*24*time.Hour)
+		opts := append(cfg.opts[:len(cfg.opts):len(cfg.opts)], ical.WithRevisions(st.Revisions()))
+
+		teams := sortedTeams(games)
+		for _, dir := range dirs {
+			seasonDir := filepath.Join(dir, season.Slug())
+			stats, err := output.GenerateAllCalendars(seasonDir, series, []ehl.SeasonGames{{Season: season, Games: games}}, teams, cfg.presets, opts...)
+			if err != nil {
+				return fmt.Errorf("season %s: %w", season.Name, err)
+			}
+			log.Printf("Archived %s to %s: %d files", season.Name, seasonDir, stats.FilesWritten)
+		}
+	}
+
+	return nil
+}
+
+// fetchSeasonGames returns the games of a season, from the cache if the season is complete
+func fetchSeasonGames(ctx context.Context, client *ehl.Client, store *cache.Store, series ehl.Series, season ehl.Season) ([]ehl.Game, error) {
+	games, ok, err := store.Load(series.UUID, season.UUID)
+	if err != nil {
+		log.Printf("Ignoring cache for season %s: %v", season.Name, err)
+	} else if ok {
+		log.Printf("Season %s: %d games (cached)", season.Name, len(games))
+		return games, nil
+	}
+
+	games, err = client.FetchGames(ctx, season.UUID)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Season %s: %d games", season.Name, len(games))
+
+	if ehl.SeasonComplete(games, time.Now()) {
+		if err := store.Save(series.UUID, season.UUID, games); err != nil {
+			log.Printf("Failed to cache season %s: %v", season.Name, err)
+		}
+	}
+
+	return games, nil
+}
+
+// updateState records the games of a season in st and returns the games to publish.
+// When most of the season is missing, the fetched games are published with a warning.
+func updateState(st *state.State, season ehl.Season, games []ehl.Game, now time.Time, grace time.Duration) []ehl.Game {
+	published, err := st.Update(season.UUID, games, now, grace)
+	if err != nil {
+		log.Printf("Warning: %s: %v, not removing the missing games (use -accept-removals if the schedule was reissued)", season.Name, err)
+	}
+	return published
+}
+
+// writeChanges writes changes.json and the human-readable changes.txt to dir
+func writeChanges(dir string, changes []diff.Change, loc *time.Location, now time.Time) error {
+	if err := diff.WriteJSON(dir, changes, now); err != nil {
+		return err
+	}
+
+	f, err := os.Create(filepath.Join(dir, "changes.txt"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return diff.WriteSummary(f, changes, loc)
+}
+
+// writeStandings writes the regular season table of a season as api/standings.json, its
+// versioned copy api/v1/standings.json and standings.html
+func writeStandings(dir string, series ehl.Series, current ehl.SeasonGames, now time.Time, loc *time.Location) error {
+	table := standings.Compute(standings.RegularSeason(current.Games))
+
+	for _, apiDir := range []string{filepath.Join(dir, "api"), filepath.Join(dir, "api", api.Version)} {
+		if err := api.WriteStandings(apiDir, current.Season, table); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.Create(filepath.Join(dir, "standings.html"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return standings.WriteHTML(f, fmt.Sprintf("Tabell - %s %s", series.Name, current.Season.Name), table, now, loc)
+}
+
+// sortedTeams extracts the teams of a list of games, sorted by name
+func sortedTeams(games []ehl.Game) []ehl.Team {
+	teams := ehl.ExtractTeams(games)
+	sort.Slice(teams, func(i, j int) bool {
+		return teams[i].ShortName < teams[j].ShortName
+	})
+	return teams
 }
 
 func copyWebFiles(outputDir string) error {
@@ -102,8 +565,8 @@ func copyFile(src, dst string) error {
 	return err
 }
 
-func generateTeamList(teams []ehl.Team) {
-	fmt.Println("\nTeams for HTML page:")
+func generateTeamList(series ehl.Series, teams []ehl.Team) {
+	fmt.Printf("\nTeams for HTML page (%s):\n", series.Name)
 	for _, team := range teams {
 		fmt.Printf(`  {name: "%s", slug: "%s"},`+"\n", team.ShortName, team.Slug())
 	}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/thomasoddsund/hockeykalender/internal/api"
+	"github.com/thomasoddsund/hockeykalender/internal/diff"
+	"github.com/thomasoddsund/hockeykalender/internal/ehl"
+	"github.com/thomasoddsund/hockeykalender/internal/export"
+	"github.com/thomasoddsund/hockeykalender/internal/ical"
+	"github.com/thomasoddsund/hockeykalender/internal/output"
+	"github.com/thomasoddsund/hockeykalender/internal/standings"
+	"github.com/thomasoddsund/hockeykalender/internal/state"
+	"github.com/thomasoddsund/hockeykalender/internal/watch"
+)
+
+// watchSeries keeps the feeds of a series current until ctx is cancelled. It polls the
+// games of the season found by generateSeries and only rewrites the calendars of games
+// that changed, plus the JSON API, exports, change reports and Atom feeds when needed.
+// Between games it also checks for a new season and lets the previous season's tail age
+// out, regenerating the whole series when either happens.
+func watchSeries(ctx context.Context, cfg config, series ehl.Series, dirs []string, run seriesRun) {
+	client := ehl.NewSeriesClient(ehl.DefaultBaseURL, series.UUID, cfg.client...)
+
+	statePath := filepath.Join(cfg.stateDir, series.Slug+".json")
+	st, err := state.Load(statePath)
+	if err != nil {
+		log.Printf("Not watching %s: %v", series.Name, err)
+		return
+	}
+	st.AcceptRemovals = cfg.accept
+
+	feeds := seriesFeeds(cfg.feeds, ehl.ExtractTeams(ehl.MergeSeasonGames(run.seasons)))
+
+	// Each poll works on copies of the state and games, committed once every file is written,
+	// so a failed write is retried on the next poll
+	poll := func(ctx context.Context) ([]ehl.Game, error) {
+		now := time.Now()
+		current := len(run.seasons) - 1
+
+		if cfg.policy.Interval(run.seasons[current].Games, now) > cfg.policy.GameDay {
+			stale, err := seasonsStale(ctx, client, cfg, run, now)
+			if err != nil {
+				return nil, err
+			}
+			if stale {
+				if err := st.Save(statePath); err != nil {
+					return nil, fmt.Errorf("failed to save state: %w", err)
+				}
+				regenerate := cfg
+				regenerate.archive = false
+				next, err := generateSeries(ctx, regenerate, series, dirs)
+				if err != nil {
+					return nil, err
+				}
+				if st, err = state.Load(statePath); err != nil {
+					return nil, err
+				}
+				st.AcceptRemovals = cfg.accept
+				run = next
+				feeds = seriesFeeds(cfg.feeds, ehl.ExtractTeams(ehl.MergeSeasonGames(run.seasons)))
+				log.Printf("Watching %s %s", series.Name, run.season.Name)
+				return run.seasons[len(run.seasons)-1].Games, nil
+			}
+		}
+
+		games, err := client.FetchGames(ctx, run.season.UUID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s games: %w", series.Name, err)
+		}
+
+		next := st.Clone()
+		changes := next.Compare(run.season.UUID, games)
+		published := updateState(next, run.season, games, now, time.Duration(cfg.graceDays)*24*time.Hour)
+
+		affected := diff.Affected(run.seasons[current].Games, published)
+		// Upcoming events describe the table and form, which a posted result changes for other teams too
+		before := standings.Compute(standings.RegularSeason(run.seasons[current].Games))
+		after := standings.Compute(standings.RegularSeason(published))
+		affected = append(affected, upcomingGames(published, standings.Changed(before, after))...)
+		if len(affected) == 0 {
+			st = next
+			return games, nil
+		}
+		updated := slices.Clone(run.seasons)
+		updated[current].Games = published
+		catalog := slices.Clone(run.catalog)
+		for i := range catalog {
+			if catalog[i].Season.UUID == run.season.UUID {
+				catalog[i].Games = games
+			}
+		}
+
+		allGames := ehl.MergeSeasonGames(updated)
+		teams := sortedTeams(allGames)
+		next.Changes = next.Changes.Add(changes, now, now.AddDate(0, 0, -cfg.feedDays))
+		next.UpdateDescriptions(ical.Descriptions(updated, cfg.opts...), now)
+		opts := append(cfg.opts[:len(cfg.opts):len(cfg.opts)], ical.WithRevisions(next.Revisions()))
+
+		for _, dir := range dirs {
+			stats, err := output.GenerateAffectedCalendars(dir, series, updated, teams, ehl.ExtractVenues(allGames), feeds, affected, cfg.presets, opts...)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate calendars: %w", err)
+			}
+			log.Printf("%s: %d games changed, rewrote %d files in %s", series.Name, len(affected), stats.FilesWritten, dir)
+
+			// Scores and states are in the JSON API and exports as well
+			if _, err := api.WriteAll(filepath.Join(dir, "api", api.Version), catalog, updated, teams); err != nil {
+				return nil, fmt.Errorf("failed to write JSON API: %w", err)
+			}
+			if _, err := export.WriteAll(dir, series.Slug, allGames, teams, cfg.loc); err != nil {
+				return nil, fmt.Errorf("failed to write exports: %w", err)
+			}
+			if err := writeStandings(dir, series, updated[current], now, cfg.loc); err != nil {
+				return nil, fmt.Errorf("failed to write standings: %w", err)
+			}
+
+			if len(changes) == 0 {
+				continue
+			}
+			if err := writeChanges(dir, changes, cfg.loc, now); err != nil {
+				return nil, fmt.Errorf("failed to write change report: %w", err)
+			}
+			if _, err := output.GenerateAllFeeds(dir, series, teams, next.Changes, cfg.loc, now); err != nil {
+				return nil, fmt.Errorf("failed to generate feeds: %w", err)
+			}
+		}
+
+		if err := next.Save(statePath); err != nil {
+			return nil, fmt.Errorf("failed to save state: %w", err)
+		}
+		st, run.seasons, run.catalog = next, updated, catalog
+
+		return games, nil
+	}
+
+	log.Printf("Watching %s %s", series.Name, run.season.Name)
+	watch.Run(ctx, watch.RealClock, cfg.policy, run.seasons[len(run.seasons)-1].Games, poll)
+}
+
+// seasonsStale reports whether the published seasons need regenerating as a whole: a new
+// season has become current, or games of the previous season left the overlap window
+func seasonsStale(ctx context.Context, client *ehl.Client, cfg config, run seriesRun, now time.Time) (bool, error) {
+	if cfg.season == "" {
+		season, _, err := client.CurrentSeason(ctx, now)
+		if err != nil {
+			return false, fmt.Errorf("failed to get current season: %w", err)
+		}
+		if season.UUID != run.season.UUID {
+			log.Printf("Season %s is now current, regenerating", season.Name)
+			return true, nil
+		}
+	}
+
+	if len(run.seasons) > 1 {
+		tail := run.seasons[0].Games
+		if len(ehl.GamesSince(tail, now.AddDate(0, 0, -cfg.overlapDays))) < len(tail) {
+			log.Printf("Games of %s left the overlap window, regenerating", run.seasons[0].Season.Name)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// upcomingGames returns the games that have not started and involve any of teams
+func upcomingGames(games []ehl.Game, teams []string) []ehl.Game {
+	var upcoming []ehl.Game
+	for _, game := range games {
+		if game.State.Started() || game.IsCancelled() {
+			continue
+		}
+		for _, team := range teams {
+			if game.InvolvesTeam(team) {
+				upcoming = append(upcoming, game)
+				break
+			}
+		}
+	}
+	return upcoming
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/thomasoddsund/hockeykalender/internal/ehl"
+	"github.com/thomasoddsund/hockeykalender/internal/ical"
+	"github.com/thomasoddsund/hockeykalender/internal/server"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "Address to listen on")
+	refresh := flag.Duration("refresh", 15*time.Minute, "How often to refresh games from the EHL API")
+	overlapDays := flag.Int("overlap-days", 60, "Keep the previous season's games from the last N days in the feeds (0 to disable)")
+	graceDays := flag.Int("cancelled-grace-days", 14, "Keep cancelled, postponed and removed games as cancelled events for N days")
+	webDir := flag.String("web", "web", "Directory with the landing page, served at /")
+	timeZone := flag.String("timezone", ical.DefaultTimeZone, "Time zone for event times, empty for UTC")
+	seriesSpec := flag.String("series", "", "Series to serve as slug=uuid[:name] (default EHL)")
+	stateDir := flag.String("state", ".cache/serve", "Directory for per-series state kept between restarts, not shared with a running cmd/generate (empty to keep it in memory)")
+	links := ical.DefaultLinks
+	flag.StringVar(&links.Game, "game-url", links.Game, "URL template of the game page linked from events, {game} is the game UUID (empty to disable)")
+	flag.StringVar(&links.Venue, "venue-url", links.Venue, "URL template of the venue map linked from events, {venue} is the venue name (empty to disable)")
+	timeout := flag.Duration("timeout", ehl.DefaultTimeout, "Timeout of each request to the EHL API (0 for none)")
+	retry := ehl.DefaultRetryPolicy()
+	flag.IntVar(&retry.Retries, "retries", retry.Retries, "Retries of failed requests to the EHL API, with exponential backoff")
+	flag.Parse()
+
+	series := ehl.DefaultSeries
+	if *seriesSpec != "" {
+		var err error
+		if series, err = ehl.ParseSeries(*seriesSpec); err != nil {
+			log.Fatalf("Invalid -series: %v", err)
+		}
+	}
+
+	if err := links.Validate(); err != nil {
+		log.Fatalf("Invalid link template: %v", err)
+	}
+
+	if *timeout < 0 || retry.Retries < 0 {
+		log.Fatalf("Invalid -timeout or -retries: must not be negative")
+	}
+
+	opts := []ical.Option{ical.WithSeasonContext(), ical.WithLinks(links), ical.WithVenues(ehl.DefaultVenues)}
+	if *timeZone != "" {
+		loc, err := time.LoadLocation(*timeZone)
+		if err != nil {
+			log.Fatalf("Invalid -timezone: %v", err)
+		}
+		opts = append(opts, ical.WithTimeZone(loc))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	client := ehl.NewSeriesClient(ehl.DefaultBaseURL, series.UUID, ehl.WithTimeout(*timeout), ehl.WithRetryPolicy(retry))
+	srv := server.New(client, series, time.Duration(*overlapDays)*24*time.Hour, time.Duration(*graceDays)*24*time.Hour, opts...)
+	if *stateDir != "" {
+		if err := srv.LoadState(filepath.Join(*stateDir, series.Slug+".json")); err != nil {
+			log.Fatalf("Failed to load state: %v", err)
+		}
+	}
+
+	log.Printf("Fetching %s games...", series.Name)
+	if err := srv.Refresh(ctx); err != nil {
+		log.Fatalf("Initial refresh failed: %v", err)
+	}
+	go srv.Run(ctx, *refresh)
+
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           calendarOrFile(srv, http.FileServer(http.Dir(*webDir))),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		log.Printf("Listening on %s", *addr)
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Server failed: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("Shutting down...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		log.Fatalf("Shutdown failed: %v", err)
+	}
+
+	log.Println("Done!")
+}
+
+// calendarOrFile sends .ics requests to the calendar server and everything else to files
+func calendarOrFile(calendars, files http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if filepath.Ext(r.URL.Path) == ".ics" {
+			calendars.ServeHTTP(w, r)
+			return
+		}
+		files.ServeHTTP(w, r)
+	})
+}
+
+func init() {
+	log.SetFlags(log.LstdFlags | log.Lshortfile)
+	log.SetOutput(os.Stderr)
+}
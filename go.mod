@@ -2,4 +2,4 @@ module github.com/thomasoddsund/hockeykalender
 
 go 1.25.5
 
-require golang.org/x/text v0.33.0 // indirect
+require golang.org/x/text v0.33.0
@@ -0,0 +1,257 @@
+package api
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/thomasoddsund/hockeykalender/internal/ehl"
+	"github.com/thomasoddsund/hockeykalender/internal/standings"
+)
+
+// Version is the schema version, part of the API path (api/v1/)
+const Version = "v1"
+
+// Schema is the JSON Schema of all v1 documents, published as api/v1/schema.json
+//
+//go:embed schema/v1.json
+var Schema []byte
+
+// Team is a team in the teams document and in games
+type Team struct {
+	UUID     string `json:"uuid"`
+	Slug     string `json:"slug"`
+	Name     string `json:"name"`
+	FullName string `json:"fullName,omitempty"`
+	Code     string `json:"code,omitempty"`
+	Icon     string `json:"icon,omitempty"`
+}
+
+// Season is a season in the seasons document
+type Season struct {
+	UUID      string     `json:"uuid"`
+	Slug      string     `json:"slug"`
+	Name      string     `json:"name"`
+	Games     int        `json:"games"`
+	FirstGame *time.Time `json:"firstGame,omitempty"`
+	LastGame  *time.Time `json:"lastGame,omitempty"`
+}
+
+// Score is the result of a game that has started
+type Score struct {
+	Home     int    `json:"home"`
+	Away     int    `json:"away"`
+	Decision string `json:"decision,omitempty"` // "overtime" or "shootout" for finished games
+}
+
+// Game is a game in the games documents. Times are UTC in RFC 3339 format.
+type Game struct {
+	UUID      string    `json:"uuid"`
+	Season    string    `json:"season"` // Season UUID
+	StartTime time.Time `json:"startTime"`
+	State     string    `json:"state"`
+	GameType  string    `json:"gameType,omitempty"`
+	Venue     string    `json:"venue"`
+	HomeTeam  Team      `json:"homeTeam"`
+	AwayTeam  Team      `json:"awayTeam"`
+	Score     *Score    `json:"score,omitempty"`
+}
+
+// Standing is a team's line in the standings document
+type Standing struct {
+	Position         int  `json:"position"`
+	Team             Team `json:"team"`
+	Played           int  `json:"played"`
+	RegulationWins   int  `json:"regulationWins"`
+	OvertimeWins     int  `json:"overtimeWins"`   // Wins in overtime or shootout
+	OvertimeLosses   int  `json:"overtimeLosses"` // Losses in overtime or shootout
+	RegulationLosses int  `json:"regulationLosses"`
+	GoalsFor         int  `json:"goalsFor"`
+	GoalsAgainst     int  `json:"goalsAgainst"`
+	GoalDifference   int  `json:"goalDifference"`
+	Points           int  `json:"points"`
+}
+
+// TeamsDocument is api/v1/teams.json
+type TeamsDocument struct {
+	Version string `json:"version"`
+	Teams   []Team `json:"teams"`
+}
+
+// SeasonsDocument is api/v1/seasons.json
+type SeasonsDocument struct {
+	Version string   `json:"version"`
+	Seasons []Season `json:"seasons"`
+}
+
+// GamesDocument is api/v1/games.json and api/v1/teams/{slug}/games.json
+type GamesDocument struct {
+	Version string `json:"version"`
+	Team    string `json:"team,omitempty"` // Team slug, for a team's games
+	Games   []Game `json:"games"`
+}
+
+// StandingsDocument is api/v1/standings.json
+type StandingsDocument struct {
+	Version   string     `json:"version"`
+	Season    string     `json:"season"` // Season UUID
+	Standings []Standing `json:"standings"`
+}
+
+// NewTeam converts an ehl.Team
+func NewTeam(team ehl.Team) Team {
+	return Team{
+		UUID:     team.UUID,
+		Slug:     team.Slug(),
+		Name:     team.ShortName,
+		FullName: team.FullName,
+		Code:     team.Code,
+		Icon:     team.Icon,
+	}
+}
+
+// NewGame converts an ehl.Game of a season. The score is left out until the game is live.
+func NewGame(seasonUUID string, game ehl.Game) Game {
+	g := Game{
+		UUID:      game.UUID,
+		Season:    seasonUUID,
+		StartTime: game.StartTime.UTC(),
+		State:     string(game.State),
+		GameType:  game.GameType.Name,
+		Venue:     game.Venue.Name,
+		HomeTeam:  NewTeam(game.HomeTeam),
+		AwayTeam:  NewTeam(game.AwayTeam),
+	}
+	g.HomeTeam.Icon, g.AwayTeam.Icon = "", ""
+
+	if game.State.Started() {
+		g.Score = &Score{Home: game.HomeTeam.Score, Away: game.AwayTeam.Score}
+		if game.State == ehl.StatePostGame {
+			g.Score.Decision = string(game.Decision)
+		}
+	}
+
+	return g
+}
+
+// Teams builds the teams document
+func Teams(teams []ehl.Team) TeamsDocument {
+	doc := TeamsDocument{Version: Version, Teams: []Team{}}
+	for _, team := range teams {
+		doc.Teams = append(doc.Teams, NewTeam(team))
+	}
+	return doc
+}
+
+// Seasons builds the seasons document from the complete games of every season
+func Seasons(seasons []ehl.SeasonGames) SeasonsDocument {
+	doc := SeasonsDocument{Version: Version, Seasons: []Season{}}
+	for _, sg := range seasons {
+		s := Season{UUID: sg.Season.UUID, Slug: sg.Season.Slug(), Name: sg.Season.Name, Games: len(sg.Games)}
+		if len(sg.Games) > 0 {
+			first, last := ehl.SeasonSpan(sg.Games)
+			first, last = first.UTC(), last.UTC()
+			s.FirstGame, s.LastGame = &first, &last
+		}
+		doc.Seasons = append(doc.Seasons, s)
+	}
+	return doc
+}
+
+// Games builds a games document, of the games involving team if it is not nil
+func Games(seasons []ehl.SeasonGames, team *ehl.Team) GamesDocument {
+	doc := GamesDocument{Version: Version, Games: []Game{}}
+	if team != nil {
+		doc.Team = team.Slug()
+	}
+
+	for _, sg := range seasons {
+		for _, game := range sg.Games {
+			if team == nil || game.InvolvesTeam(team.ShortName) {
+				doc.Games = append(doc.Games, NewGame(sg.Season.UUID, game))
+			}
+		}
+	}
+	return doc
+}
+
+// Standings builds the standings document of a season
+func Standings(season ehl.Season, table standings.Table) StandingsDocument {
+	doc := StandingsDocument{Version: Version, Season: season.UUID, Standings: []Standing{}}
+	for _, row := range table {
+		team := NewTeam(row.Team)
+		team.Icon = ""
+		doc.Standings = append(doc.Standings, Standing{
+			Position:         row.Position,
+			Team:             team,
+			Played:           row.Played,
+			RegulationWins:   row.RegulationWins,
+			OvertimeWins:     row.OvertimeWins,
+			OvertimeLosses:   row.OvertimeLosses,
+			RegulationLosses: row.RegulationLosses,
+			GoalsFor:         row.GoalsFor,
+			GoalsAgainst:     row.GoalsAgainst,
+			GoalDifference:   row.GoalDifference(),
+			Points:           row.Points,
+		})
+	}
+	return doc
+}
+
+// WriteStandings writes the standings document of a season to {dir}/standings.json
+func WriteStandings(dir string, season ehl.Season, table standings.Table) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create API directory: %w", err)
+	}
+	return writeJSON(dir, "standings.json", Standings(season, table))
+}
+
+// WriteAll writes every document and the schema to dir, which is usually {output}/api/v1.
+// catalog holds the complete games of every season, for the seasons document, and seasons
+// the published games, which may include only the tail of the previous season.
+// It returns the number of files written.
+func WriteAll(dir string, catalog, seasons []ehl.SeasonGames, teams []ehl.Team) (int, error) {
+	files := map[string]any{
+		"teams.json":   Teams(teams),
+		"seasons.json": Seasons(catalog),
+		"games.json":   Games(seasons, nil),
+	}
+	for _, team := range teams {
+		files[filepath.Join("teams", team.Slug(), "games.json")] = Games(seasons, &team)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, fmt.Errorf("failed to create API directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "schema.json"), Schema, 0644); err != nil {
+		return 0, fmt.Errorf("failed to write schema.json: %w", err)
+	}
+
+	for name, doc := range files {
+		if err := writeJSON(dir, name, doc); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(files) + 1, nil
+}
+
+// writeJSON writes a document to {dir}/{name}, creating subdirectories of name
+func writeJSON(dir, name string, doc any) error {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", name, err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
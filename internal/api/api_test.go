@@ -0,0 +1,364 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thomasoddsund/hockeykalender/internal/ehl"
+	"github.com/thomasoddsund/hockeykalender/internal/standings"
+)
+
+func testSeasons() ([]ehl.SeasonGames, []ehl.Team) {
+	vif := ehl.Team{UUID: "team-vif", Code: "VIF", FullName: "Vålerenga Ishockey", ShortName: "Vålerenga", Icon: "https://example.com/vif.png"}
+	sth := ehl.Team{UUID: "team-sth", Code: "STH", FullName: "Storhamar Ishockey", ShortName: "Storhamar"}
+	frisk := ehl.Team{UUID: "team-fa", ShortName: "Frisk Asker"}
+
+	played := ehl.Game{
+		UUID:      "game-1",
+		StartTime: time.Date(2025, 9, 11, 17, 0, 0, 0, time.UTC),
+		State:     ehl.StatePostGame,
+		HomeTeam:  vif,
+		AwayTeam:  sth,
+		Venue:     ehl.Venue{Name: "Jordal Amfi"},
+		GameType:  ehl.GameType{UUID: "type-regular", Name: "Seriespill"},
+	}
+	played.HomeTeam.Score, played.AwayTeam.Score = 3, 2
+
+	upcoming := ehl.Game{
+		UUID:      "game-2",
+		StartTime: time.Date(2025, 9, 13, 16, 0, 0, 0, time.UTC),
+		State:     ehl.StatePreGame,
+		HomeTeam:  sth,
+		AwayTeam:  frisk,
+		Venue:     ehl.Venue{Name: "CC Amfi"},
+	}
+
+	cancelled := upcoming
+	cancelled.UUID = "game-3"
+	cancelled.State = ehl.StateCancelled
+
+	seasons := []ehl.SeasonGames{
+		{Season: ehl.Season{UUID: "season-2425", Name: "2024/2025"}},
+		{Season: ehl.Season{UUID: "season-2526", Name: "2025/2026"}, Games: []ehl.Game{played, upcoming, cancelled}},
+	}
+	return seasons, []ehl.Team{frisk, sth, vif}
+}
+
+func TestNewGame(t *testing.T) {
+	seasons, _ := testSeasons()
+	games := seasons[1].Games
+
+	overtime := games[0]
+	overtime.Decision = ehl.DecisionOvertime
+	live := games[1]
+	live.State = ehl.StateLive
+	live.HomeTeam.Score = 1
+	live.Decision = ehl.DecisionOvertime // ignored until the game is over
+
+	tests := []struct {
+		name  string
+		game  ehl.Game
+		score *Score
+	}{
+		{"played", games[0], &Score{Home: 3, Away: 2}},
+		{"upcoming", games[1], nil},
+		{"cancelled", games[2], nil},
+		{"overtime", overtime, &Score{Home: 3, Away: 2, Decision: "overtime"}},
+		{"live", live, &Score{Home: 1, Away: 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewGame("season-2526", tt.game)
+			if !reflect.DeepEqual(got.Score, tt.score) {
+				t.Errorf("expected score %v, got %v", tt.score, got.Score)
+			}
+			if got.Season != "season-2526" || got.HomeTeam.Slug == "" || got.StartTime.Location() != time.UTC {
+				t.Errorf("unexpected game: %+v", got)
+			}
+		})
+	}
+}
+
+func TestGames_Team(t *testing.T) {
+	seasons, teams := testSeasons()
+
+	tests := []struct {
+		team     ehl.Team
+		expected []string
+	}{
+		{teams[0], []string{"game-2", "game-3"}},
+		{teams[1], []string{"game-1", "game-2", "game-3"}},
+		{teams[2], []string{"game-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.team.ShortName, func(t *testing.T) {
+			doc := Games(seasons, &tt.team)
+			var uuids []string
+			for _, g := range doc.Games {
+				uuids = append(uuids, g.UUID)
+			}
+			if strings.Join(uuids, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("expected %v, got %v", tt.expected, uuids)
+			}
+			if doc.Team != tt.team.Slug() {
+				t.Errorf("expected team %q, got %q", tt.team.Slug(), doc.Team)
+			}
+		})
+	}
+}
+
+func TestWriteAll_ConformsToSchema(t *testing.T) {
+	dir := t.TempDir()
+	seasons, teams := testSeasons()
+
+	files, err := WriteAll(dir, seasons, seasons, teams)
+	if err != nil {
+		t.Fatalf("WriteAll failed: %v", err)
+	}
+	// schema, teams, seasons, games + 3 team game lists
+	if files != 7 {
+		t.Errorf("expected 7 files, got %d", files)
+	}
+
+	schema := loadSchema(t)
+
+	tests := []struct {
+		file string
+		def  string
+	}{
+		{"teams.json", "teamsDocument"},
+		{"seasons.json", "seasonsDocument"},
+		{"games.json", "gamesDocument"},
+		{"teams/valerenga/games.json", "gamesDocument"},
+		{"teams/frisk-asker/games.json", "gamesDocument"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			doc := readJSON(t, filepath.Join(dir, tt.file))
+			if err := validate(schema, schema["$defs"].(map[string]any)[tt.def], doc); err != nil {
+				t.Errorf("%s does not conform to %s: %v", tt.file, tt.def, err)
+			}
+			// The top-level schema accepts every document
+			if err := validate(schema, schema, doc); err != nil {
+				t.Errorf("%s does not conform to the schema: %v", tt.file, err)
+			}
+		})
+	}
+
+	written := readJSON(t, filepath.Join(dir, "schema.json"))
+	if written.(map[string]any)["$id"] != schema["$id"] {
+		t.Error("expected schema.json to be the embedded schema")
+	}
+}
+
+func TestWriteAll_SeasonsFromCatalog(t *testing.T) {
+	dir := t.TempDir()
+	catalog, teams := testSeasons()
+	older := ehl.SeasonGames{Season: ehl.Season{UUID: "season-2324", Name: "2023/2024"}}
+	catalog = append([]ehl.SeasonGames{older}, catalog...)
+
+	// The published games only hold the tail of the current season
+	published := []ehl.SeasonGames{{Season: catalog[2].Season, Games: catalog[2].Games[1:]}}
+	if _, err := WriteAll(dir, catalog, published, teams); err != nil {
+		t.Fatalf("WriteAll failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "seasons.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc SeasonsDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(doc.Seasons) != 3 || doc.Seasons[0].UUID != "season-2324" {
+		t.Fatalf("expected every season, got %+v", doc.Seasons)
+	}
+	current := doc.Seasons[2]
+	if current.Games != 3 || !current.FirstGame.Equal(catalog[2].Games[0].StartTime) {
+		t.Errorf("expected the full season, got %d games from %v", current.Games, current.FirstGame)
+	}
+}
+
+func TestWriteStandings(t *testing.T) {
+	dir := t.TempDir()
+	seasons, _ := testSeasons()
+	current := seasons[len(seasons)-1]
+	table := standings.Compute(current.Games)
+
+	if err := WriteStandings(dir, current.Season, table); err != nil {
+		t.Fatalf("WriteStandings failed: %v", err)
+	}
+
+	doc := readJSON(t, filepath.Join(dir, "standings.json"))
+	schema := loadSchema(t)
+	if err := validate(schema, schema["$defs"].(map[string]any)["standingsDocument"], doc); err != nil {
+		t.Errorf("standings.json does not conform to standingsDocument: %v", err)
+	}
+	if err := validate(schema, schema, doc); err != nil {
+		t.Errorf("standings.json does not conform to the schema: %v", err)
+	}
+
+	got := Standings(current.Season, table)
+	if len(got.Standings) != len(table) || got.Season != current.Season.UUID {
+		t.Fatalf("unexpected document %+v", got)
+	}
+	leader := got.Standings[0]
+	if leader.Position != 1 || leader.Points != 3 || leader.GoalDifference != 1 || leader.Team.Slug != "valerenga" {
+		t.Errorf("unexpected leader %+v", leader)
+	}
+}
+
+func TestValidate_RejectsInvalidDocuments(t *testing.T) {
+	schema := loadSchema(t)
+	games := schema["$defs"].(map[string]any)["gamesDocument"]
+
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{"wrong version", `{"version":"v2","games":[]}`},
+		{"missing games", `{"version":"v1"}`},
+		{"unknown field", `{"version":"v1","games":[],"extra":1}`},
+		{"bad start time", `{"version":"v1","games":[{"uuid":"g","season":"s","startTime":"11.09.2025","state":"pre-game","venue":"","homeTeam":{"uuid":"a","slug":"a","name":"A"},"awayTeam":{"uuid":"b","slug":"b","name":"B"}}]}`},
+		{"bad slug", `{"version":"v1","team":"Vålerenga","games":[]}`},
+		{"fractional score", `{"version":"v1","games":[{"uuid":"g","season":"s","startTime":"2025-09-11T17:00:00Z","state":"post-game","venue":"","homeTeam":{"uuid":"a","slug":"a","name":"A"},"awayTeam":{"uuid":"b","slug":"b","name":"B"},"score":{"home":1.5,"away":0}}]}`},
+		{"unknown decision", `{"version":"v1","games":[{"uuid":"g","season":"s","startTime":"2025-09-11T17:00:00Z","state":"post-game","venue":"","homeTeam":{"uuid":"a","slug":"a","name":"A"},"awayTeam":{"uuid":"b","slug":"b","name":"B"},"score":{"home":1,"away":0,"decision":"penalties"}}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc any
+			if err := json.Unmarshal([]byte(tt.doc), &doc); err != nil {
+				t.Fatalf("invalid test JSON: %v", err)
+			}
+			if err := validate(schema, games, doc); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
+
+func loadSchema(t *testing.T) map[string]any {
+	t.Helper()
+	var schema map[string]any
+	if err := json.Unmarshal(Schema, &schema); err != nil {
+		t.Fatalf("invalid schema: %v", err)
+	}
+	return schema
+}
+
+func readJSON(t *testing.T, path string) any {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatalf("invalid JSON in %s: %v", path, err)
+	}
+	return v
+}
+
+// validate checks v against the subset of JSON Schema used by schema/v1.json
+func validate(root map[string]any, schema any, v any) error {
+	s := schema.(map[string]any)
+
+	if ref, ok := s["$ref"].(string); ok {
+		name, _ := strings.CutPrefix(ref, "#/$defs/")
+		def, ok := root["$defs"].(map[string]any)[name]
+		if !ok {
+			return fmt.Errorf("unknown $ref %s", ref)
+		}
+		return validate(root, def, v)
+	}
+
+	if oneOf, ok := s["oneOf"].([]any); ok {
+		matches := 0
+		for _, sub := range oneOf {
+			if validate(root, sub, v) == nil {
+				matches++
+			}
+		}
+		if matches != 1 {
+			return fmt.Errorf("matches %d of oneOf", matches)
+		}
+	}
+
+	if c, ok := s["const"]; ok && c != v {
+		return fmt.Errorf("expected %v, got %v", c, v)
+	}
+
+	if enum, ok := s["enum"].([]any); ok && !slices.Contains(enum, v) {
+		return fmt.Errorf("%v is not one of %v", v, enum)
+	}
+
+	switch s["type"] {
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("expected object, got %T", v)
+		}
+		props, _ := s["properties"].(map[string]any)
+		for _, name := range s["required"].([]any) {
+			if _, ok := obj[name.(string)]; !ok {
+				return fmt.Errorf("missing required property %s", name)
+			}
+		}
+		for name, value := range obj {
+			prop, ok := props[name]
+			if !ok {
+				if s["additionalProperties"] == false {
+					return fmt.Errorf("unexpected property %s", name)
+				}
+				continue
+			}
+			if err := validate(root, prop, value); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("expected array, got %T", v)
+		}
+		for i, item := range arr {
+			if err := validate(root, s["items"], item); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+	case "integer":
+		n, ok := v.(float64)
+		if !ok || n != float64(int64(n)) {
+			return fmt.Errorf("expected integer, got %v", v)
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("expected string, got %T", v)
+		}
+		if pattern, ok := s["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(str) {
+			return fmt.Errorf("%q does not match %s", str, pattern)
+		}
+		if s["format"] == "date-time" {
+			if _, err := time.Parse(time.RFC3339, str); err != nil {
+				return fmt.Errorf("%q is not a date-time", str)
+			}
+		}
+	}
+
+	return nil
+}
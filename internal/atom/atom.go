@@ -0,0 +1,86 @@
+package atom
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+
+	"github.com/thomasoddsund/hockeykalender/internal/diff"
+	"github.com/thomasoddsund/hockeykalender/internal/ical"
+)
+
+// idPrefix makes feed and entry IDs tag URIs (RFC 4151) in the same namespace as event UIDs
+const idPrefix = "tag:" + ical.UIDDomain + ",2025:"
+
+type feed struct {
+	XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string   `xml:"id"`
+	Title   string   `xml:"title"`
+	Updated string   `xml:"updated"`
+	Author  author   `xml:"author"`
+	Entries []entry  `xml:"entry"`
+}
+
+type author struct {
+	Name string `xml:"name"`
+}
+
+type entry struct {
+	ID       string   `xml:"id"`
+	Title    string   `xml:"title"`
+	Updated  string   `xml:"updated"`
+	Category category `xml:"category"`
+	Content  content  `xml:"content"`
+}
+
+type category struct {
+	Term string `xml:"term,attr"`
+}
+
+type content struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+// Generate builds an Atom feed of schedule change events, newest first.
+// id identifies the feed, and updated is the feed's update time when there are no events.
+// Entry IDs derive from the game UUID and change type, so a game moved twice updates one entry.
+func Generate(id, title, authorName string, events diff.History, loc *time.Location, updated time.Time) ([]byte, error) {
+	f := feed{
+		ID:      idPrefix + "feed/" + id,
+		Title:   title,
+		Updated: updated.UTC().Format(time.RFC3339),
+		Author:  author{Name: authorName},
+	}
+	if len(events) > 0 {
+		f.Updated = events[0].Detected.UTC().Format(time.RFC3339)
+	}
+
+	for _, e := range events {
+		f.Entries = append(f.Entries, entry{
+			ID:       idPrefix + "game/" + e.ID(),
+			Title:    e.Title(),
+			Updated:  e.Detected.UTC().Format(time.RFC3339),
+			Category: category{Term: string(e.Type)},
+			Content:  content{Type: "text", Text: e.Describe(loc)},
+		})
+	}
+
+	data, err := xml.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode feed: %w", err)
+	}
+
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+// Involving returns the events of games where team plays
+func Involving(events diff.History, teamName string) diff.History {
+	var filtered diff.History
+	for _, e := range events {
+		if e.HomeTeam == teamName || e.AwayTeam == teamName {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,110 @@
+package atom
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thomasoddsund/hockeykalender/internal/diff"
+	"github.com/thomasoddsund/hockeykalender/internal/ehl"
+)
+
+func testHistory() diff.History {
+	detected := time.Date(2025, 9, 1, 6, 0, 0, 0, time.UTC)
+
+	game := ehl.Game{
+		UUID:      "game-1",
+		StartTime: time.Date(2025, 9, 11, 17, 0, 0, 0, time.UTC),
+		State:     ehl.StatePreGame,
+		HomeTeam:  ehl.Team{ShortName: "Vålerenga"},
+		AwayTeam:  ehl.Team{ShortName: "Storhamar"},
+		Venue:     ehl.Venue{Name: "Jordal Amfi"},
+	}
+	moved := game
+	moved.StartTime = game.StartTime.Add(24 * time.Hour)
+
+	other := game
+	other.UUID = "game-2"
+	other.HomeTeam = ehl.Team{ShortName: "Stjernen"}
+	other.AwayTeam = ehl.Team{ShortName: "Lørenskog"}
+
+	var h diff.History
+	h = h.Add(diff.Compare(nil, []ehl.Game{other}), detected, detected.AddDate(0, 0, -30))
+	h = h.Add(diff.Compare([]ehl.Game{game}, []ehl.Game{moved}), detected.Add(time.Hour), detected.AddDate(0, 0, -30))
+	return h
+}
+
+func TestGenerate(t *testing.T) {
+	oslo, _ := time.LoadLocation("Europe/Oslo")
+
+	data, err := Generate("valerenga", "Vålerenga", "EHL", testHistory(), oslo, time.Time{})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	var f feed
+	if err := xml.Unmarshal(data, &f); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, data)
+	}
+
+	if f.ID != "tag:ehl.hockeykalender,2025:feed/valerenga" {
+		t.Errorf("unexpected feed ID %q", f.ID)
+	}
+	if f.Updated != "2025-09-01T07:00:00Z" {
+		t.Errorf("expected feed updated at newest entry, got %q", f.Updated)
+	}
+	if len(f.Entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(f.Entries))
+	}
+
+	moved := f.Entries[0]
+	if moved.ID != "tag:ehl.hockeykalender,2025:game/game-1/time_moved" {
+		t.Errorf("unexpected entry ID %q", moved.ID)
+	}
+	if moved.Title != "Flyttet: Vålerenga vs Storhamar" || moved.Category.Term != "time_moved" {
+		t.Errorf("unexpected entry: %+v", moved)
+	}
+	if moved.Content.Text != "Flyttet: Vålerenga vs Storhamar, fra 11.09.2025 19:00 til 12.09.2025 19:00" {
+		t.Errorf("unexpected content %q", moved.Content.Text)
+	}
+
+	if !strings.HasPrefix(string(data), xml.Header) || !strings.Contains(string(data), `xmlns="http://www.w3.org/2005/Atom"`) {
+		t.Errorf("expected XML header and Atom namespace:\n%s", data)
+	}
+}
+
+func TestGenerate_Empty(t *testing.T) {
+	updated := time.Date(2025, 9, 1, 6, 0, 0, 0, time.UTC)
+
+	data, err := Generate("ehl", "EHL", "EHL", nil, time.UTC, updated)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if !strings.Contains(string(data), "<updated>2025-09-01T06:00:00Z</updated>") {
+		t.Errorf("expected fallback update time:\n%s", data)
+	}
+	if strings.Contains(string(data), "<entry>") {
+		t.Errorf("expected no entries:\n%s", data)
+	}
+}
+
+func TestInvolving(t *testing.T) {
+	tests := []struct {
+		team     string
+		expected int
+	}{
+		{"Vålerenga", 1},
+		{"Storhamar", 1},
+		{"Lørenskog", 1},
+		{"Narvik", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.team, func(t *testing.T) {
+			if got := len(Involving(testHistory(), tt.team)); got != tt.expected {
+				t.Errorf("expected %d events, got %d", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/thomasoddsund/hockeykalender/internal/ehl"
+)
+
+// Store caches the games of completed seasons on disk, one JSON file per series and season
+type Store struct {
+	dir string
+}
+
+// New creates a cache store rooted at dir
+func New(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+func (s *Store) path(seriesUUID, seasonUUID string) string {
+	return filepath.Join(s.dir, seriesUUID, seasonUUID+".json")
+}
+
+// Load returns the cached games for a season, and false if the season is not cached
+func (s *Store) Load(seriesUUID, seasonUUID string) ([]ehl.Game, bool, error) {
+	data, err := os.ReadFile(s.path(seriesUUID, seasonUUID))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read cache: %w", err)
+	}
+
+	var games []ehl.Game
+	if err := json.Unmarshal(data, &games); err != nil {
+		return nil, false, fmt.Errorf("failed to decode cache: %w", err)
+	}
+
+	return games, true, nil
+}
+
+// Save writes the games of a season to the cache
+func (s *Store) Save(seriesUUID, seasonUUID string, games []ehl.Game) error {
+	path := s.path(seriesUUID, seasonUUID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.Marshal(games)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache: %w", err)
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thomasoddsund/hockeykalender/internal/ehl"
+)
+
+func TestStoreRoundTrip(t *testing.T) {
+	store := New(t.TempDir())
+
+	games := []ehl.Game{
+		{
+			UUID:      "game-1",
+			StartTime: time.Date(2024, 9, 11, 17, 0, 0, 0, time.UTC),
+			State:     "post-game",
+			HomeTeam:  ehl.Team{UUID: "team-vif", Code: "VIF", FullName: "Vålerenga Ishockey Elite", ShortName: "Vålerenga", Score: 3},
+			AwayTeam:  ehl.Team{UUID: "team-sth", Code: "STH", FullName: "Storhamar Ishockey Elite", ShortName: "Storhamar", Score: 2},
+			Venue:     ehl.Venue{Name: "Jordal Amfi"},
+			GameType:  ehl.GameType{UUID: "qQ9-af37Ti40B", Name: "Serie"},
+		},
+	}
+
+	if err := store.Save("series", "season", games); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, ok, err := store.Load("series", "season")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected season to be cached")
+	}
+
+	if len(loaded) != 1 {
+		t.Fatalf("expected 1 game, got %d", len(loaded))
+	}
+	if loaded[0] != games[0] {
+		t.Errorf("cached game mismatch:\n got  %+v\n want %+v", loaded[0], games[0])
+	}
+}
+
+func TestStoreMiss(t *testing.T) {
+	store := New(t.TempDir())
+
+	games, ok, err := store.Load("series", "unknown")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if ok || games != nil {
+		t.Error("expected cache miss")
+	}
+}
@@ -0,0 +1,267 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/thomasoddsund/hockeykalender/internal/ehl"
+)
+
+// ChangeType classifies a change to a game between two runs
+type ChangeType string
+
+const (
+	Added        ChangeType = "added"
+	Removed      ChangeType = "removed" // Also used when a game is cancelled or postponed
+	TimeMoved    ChangeType = "time_moved"
+	VenueChanged ChangeType = "venue_changed"
+	ResultPosted ChangeType = "result_posted"
+)
+
+// Change describes one change to a game. A game can have several changes, e.g. a new time and venue.
+type Change struct {
+	Type          ChangeType `json:"type"`
+	GameUUID      string     `json:"gameUuid"`
+	HomeTeam      string     `json:"homeTeam"`
+	AwayTeam      string     `json:"awayTeam"`
+	StartTime     time.Time  `json:"startTime"`
+	Venue         string     `json:"venue,omitempty"`
+	PreviousStart *time.Time `json:"previousStartTime,omitempty"`
+	PreviousVenue string     `json:"previousVenue,omitempty"`
+	HomeScore     *int       `json:"homeScore,omitempty"`
+	AwayScore     *int       `json:"awayScore,omitempty"`
+	Decision      string     `json:"decision,omitempty"` // "overtime" or "shootout" for results
+}
+
+// Compare returns the changes from previous to current, ordered by start time and type
+func Compare(previous, current []ehl.Game) []Change {
+	before := make(map[string]ehl.Game, len(previous))
+	for _, game := range previous {
+		before[game.UUID] = game
+	}
+
+	var changes []Change
+	seen := make(map[string]bool, len(current))
+
+	for _, game := range current {
+		seen[game.UUID] = true
+		old, existed := before[game.UUID]
+
+		switch {
+		case !existed:
+			if !game.IsCancelled() {
+				changes = append(changes, newChange(Added, game))
+			}
+			continue
+		case game.IsCancelled():
+			if !old.IsCancelled() {
+				changes = append(changes, newChange(Removed, game))
+			}
+			continue
+		}
+
+		if !game.StartTime.Equal(old.StartTime) {
+			c := newChange(TimeMoved, game)
+			start := old.StartTime
+			c.PreviousStart = &start
+			changes = append(changes, c)
+		}
+		if game.Venue.Name != old.Venue.Name {
+			c := newChange(VenueChanged, game)
+			c.PreviousVenue = old.Venue.Name
+			changes = append(changes, c)
+		}
+		if game.State == ehl.StatePostGame && (old.State != ehl.StatePostGame || game.Decision != old.Decision ||
+			game.HomeTeam.Score != old.HomeTeam.Score || game.AwayTeam.Score != old.AwayTeam.Score) {
+			c := newChange(ResultPosted, game)
+			home, away := game.HomeTeam.Score, game.AwayTeam.Score
+			c.HomeScore, c.AwayScore = &home, &away
+			c.Decision = string(game.Decision)
+			changes = append(changes, c)
+		}
+	}
+
+	for _, game := range previous {
+		if !seen[game.UUID] && !game.IsCancelled() {
+			changes = append(changes, newChange(Removed, game))
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if !changes[i].StartTime.Equal(changes[j].StartTime) {
+			return changes[i].StartTime.Before(changes[j].StartTime)
+		}
+		return changes[i].Type < changes[j].Type
+	})
+
+	return changes
+}
+
+// Affected returns the games whose data differs between previous and current, including
+// live score updates that Compare does not report. Both versions of a changed game are
+// returned, so feeds the game moved out of are affected as well.
+func Affected(previous, current []ehl.Game) []ehl.Game {
+	before := make(map[string]ehl.Game, len(previous))
+	for _, game := range previous {
+		before[game.UUID] = game
+	}
+
+	var affected []ehl.Game
+	for _, game := range current {
+		old, existed := before[game.UUID]
+		delete(before, game.UUID)
+		if existed && sameGame(old, game) {
+			continue
+		}
+		if existed {
+			affected = append(affected, old)
+		}
+		affected = append(affected, game)
+	}
+	for _, game := range previous {
+		if _, removed := before[game.UUID]; removed {
+			affected = append(affected, game)
+		}
+	}
+
+	return affected
+}
+
+func sameGame(a, b ehl.Game) bool {
+	dataA, errA := json.Marshal(a)
+	dataB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(dataA) == string(dataB)
+}
+
+func newChange(t ChangeType, game ehl.Game) Change {
+	return Change{
+		Type:      t,
+		GameUUID:  game.UUID,
+		HomeTeam:  game.HomeTeam.ShortName,
+		AwayTeam:  game.AwayTeam.ShortName,
+		StartTime: game.StartTime.UTC(),
+		Venue:     game.Venue.Name,
+	}
+}
+
+// Report is the machine-readable result of a comparison
+type Report struct {
+	Generated time.Time `json:"generated"`
+	Changes   []Change  `json:"changes"`
+}
+
+// WriteJSON writes the changes as JSON to {dir}/changes.json
+func WriteJSON(dir string, changes []Change, generated time.Time) error {
+	if changes == nil {
+		changes = []Change{}
+	}
+
+	data, err := json.MarshalIndent(Report{Generated: generated.UTC(), Changes: changes}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode changes: %w", err)
+	}
+
+	return os.WriteFile(filepath.Join(dir, "changes.json"), append(data, '\n'), 0644)
+}
+
+// ID identifies a change by game and change type, stable across runs
+func (c Change) ID() string {
+	return c.GameUUID + "/" + string(c.Type)
+}
+
+// Title is a short Norwegian headline for the change
+func (c Change) Title() string {
+	match := fmt.Sprintf("%s vs %s", c.HomeTeam, c.AwayTeam)
+
+	switch c.Type {
+	case Added:
+		return "Ny kamp: " + match
+	case Removed:
+		return "Fjernet/avlyst: " + match
+	case TimeMoved:
+		return "Flyttet: " + match
+	case VenueChanged:
+		return "Ny arena: " + match
+	case ResultPosted:
+		title := fmt.Sprintf("Resultat: %s %d - %d %s", c.HomeTeam, *c.HomeScore, *c.AwayScore, c.AwayTeam)
+		if note := ehl.Decision(c.Decision).Abbreviation(); note != "" {
+			title += " (" + note + ")"
+		}
+		return title
+	}
+	return match
+}
+
+// Describe is the title with the details of the change, with times in loc
+func (c Change) Describe(loc *time.Location) string {
+	const layout = "02.01.2006 15:04"
+	start := c.StartTime.In(loc).Format(layout)
+
+	switch c.Type {
+	case Added:
+		return fmt.Sprintf("%s, %s, %s", c.Title(), start, c.Venue)
+	case Removed:
+		return fmt.Sprintf("%s, %s", c.Title(), start)
+	case TimeMoved:
+		return fmt.Sprintf("%s, fra %s til %s", c.Title(), c.PreviousStart.In(loc).Format(layout), start)
+	case VenueChanged:
+		return fmt.Sprintf("%s, %s, fra %s til %s", c.Title(), start, c.PreviousVenue, c.Venue)
+	}
+	return c.Title()
+}
+
+// Event is a change and the run that detected it
+type Event struct {
+	Change
+	Detected time.Time `json:"detected"`
+}
+
+// History is the recent changes of a series, newest first, with one event per change ID
+type History []Event
+
+// Add records changes detected at now, replacing older events with the same ID,
+// and drops events detected before since
+func (h History) Add(changes []Change, now, since time.Time) History {
+	now = now.UTC().Truncate(time.Second)
+	replaced := make(map[string]bool, len(changes))
+
+	var events History
+	for _, c := range changes {
+		replaced[c.ID()] = true
+		events = append(events, Event{Change: c, Detected: now})
+	}
+	for _, e := range h {
+		if !replaced[e.ID()] && !e.Detected.Before(since) {
+			events = append(events, e)
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Detected.After(events[j].Detected)
+	})
+
+	return events
+}
+
+// WriteSummary writes a human-readable Norwegian summary of the changes, with times in loc
+func WriteSummary(w io.Writer, changes []Change, loc *time.Location) error {
+	if len(changes) == 0 {
+		_, err := fmt.Fprintln(w, "Ingen endringer i terminlisten.")
+		return err
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d endringer i terminlisten:\n", len(changes))
+	for _, c := range changes {
+		fmt.Fprintf(&sb, "- %s\n", c.Describe(loc))
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
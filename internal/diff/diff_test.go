@@ -0,0 +1,275 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thomasoddsund/hockeykalender/internal/ehl"
+)
+
+var oslo, _ = time.LoadLocation("Europe/Oslo")
+
+func testGame(uuid string) ehl.Game {
+	return ehl.Game{
+		UUID:      uuid,
+		StartTime: time.Date(2025, 9, 11, 17, 0, 0, 0, time.UTC),
+		State:     ehl.StatePreGame,
+		HomeTeam:  ehl.Team{UUID: "team-vif", ShortName: "Vålerenga"},
+		AwayTeam:  ehl.Team{UUID: "team-sth", ShortName: "Storhamar"},
+		Venue:     ehl.Venue{Name: "Jordal Amfi"},
+	}
+}
+
+func TestCompare(t *testing.T) {
+	base := testGame("game-1")
+
+	moved := base
+	moved.StartTime = base.StartTime.Add(24 * time.Hour)
+
+	relocated := base
+	relocated.Venue.Name = "CC Amfi"
+
+	movedAndRelocated := moved
+	movedAndRelocated.Venue.Name = "CC Amfi"
+
+	played := base
+	played.State = ehl.StatePostGame
+	played.HomeTeam.Score = 3
+	played.AwayTeam.Score = 2
+
+	corrected := played
+	corrected.AwayTeam.Score = 1
+
+	shutout := base
+	shutout.State = ehl.StatePostGame
+
+	overtime := corrected
+	overtime.Decision = ehl.DecisionOvertime
+
+	live := base
+	live.State = ehl.StateLive
+	live.HomeTeam.Score = 1
+
+	cancelled := base
+	cancelled.State = ehl.StateCancelled
+
+	tests := []struct {
+		name     string
+		previous []ehl.Game
+		current  []ehl.Game
+		expected []ChangeType
+	}{
+		{"unchanged", []ehl.Game{base}, []ehl.Game{base}, nil},
+		{"added", nil, []ehl.Game{base}, []ChangeType{Added}},
+		{"added cancelled", nil, []ehl.Game{cancelled}, nil},
+		{"removed", []ehl.Game{base}, nil, []ChangeType{Removed}},
+		{"cancelled", []ehl.Game{base}, []ehl.Game{cancelled}, []ChangeType{Removed}},
+		{"still cancelled", []ehl.Game{cancelled}, []ehl.Game{cancelled}, nil},
+		{"removed after cancel", []ehl.Game{cancelled}, nil, nil},
+		{"time moved", []ehl.Game{base}, []ehl.Game{moved}, []ChangeType{TimeMoved}},
+		{"venue changed", []ehl.Game{base}, []ehl.Game{relocated}, []ChangeType{VenueChanged}},
+		{"time and venue", []ehl.Game{base}, []ehl.Game{movedAndRelocated}, []ChangeType{TimeMoved, VenueChanged}},
+		{"result posted", []ehl.Game{base}, []ehl.Game{played}, []ChangeType{ResultPosted}},
+		{"scoreless result", []ehl.Game{base}, []ehl.Game{shutout}, []ChangeType{ResultPosted}},
+		{"result corrected", []ehl.Game{played}, []ehl.Game{corrected}, []ChangeType{ResultPosted}},
+		{"result unchanged", []ehl.Game{played}, []ehl.Game{played}, nil},
+		{"decision added", []ehl.Game{corrected}, []ehl.Game{overtime}, []ChangeType{ResultPosted}},
+		{"live score", []ehl.Game{base}, []ehl.Game{live}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := Compare(tt.previous, tt.current)
+
+			var types []ChangeType
+			for _, c := range changes {
+				types = append(types, c.Type)
+			}
+			if !slices.Equal(types, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, types)
+			}
+		})
+	}
+}
+
+func TestCompare_Details(t *testing.T) {
+	base := testGame("game-1")
+	changed := base
+	changed.StartTime = base.StartTime.Add(time.Hour)
+	changed.Venue.Name = "CC Amfi"
+
+	changes := Compare([]ehl.Game{base}, []ehl.Game{changed})
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %d", len(changes))
+	}
+
+	if c := changes[0]; c.PreviousStart == nil || !c.PreviousStart.Equal(base.StartTime) || !c.StartTime.Equal(changed.StartTime) {
+		t.Errorf("time moved: got %+v", c)
+	}
+	if c := changes[1]; c.PreviousVenue != "Jordal Amfi" || c.Venue != "CC Amfi" {
+		t.Errorf("venue changed: got %+v", c)
+	}
+}
+
+func TestCompare_Order(t *testing.T) {
+	early := testGame("game-1")
+	late := testGame("game-2")
+	late.StartTime = early.StartTime.Add(48 * time.Hour)
+
+	changes := Compare([]ehl.Game{late}, []ehl.Game{early})
+	if len(changes) != 2 || changes[0].GameUUID != "game-1" || changes[1].GameUUID != "game-2" {
+		t.Errorf("expected changes ordered by start time, got %+v", changes)
+	}
+}
+
+func TestAffected(t *testing.T) {
+	base := testGame("game-1")
+	other := testGame("game-2")
+
+	live := base
+	live.State = ehl.StateLive
+	goal := live
+	goal.HomeTeam.Score = 1
+
+	relocated := base
+	relocated.Venue.Name = "CC Amfi"
+
+	tests := []struct {
+		name     string
+		previous []ehl.Game
+		current  []ehl.Game
+		expected []ehl.Game
+	}{
+		{"unchanged", []ehl.Game{base, other}, []ehl.Game{base, other}, nil},
+		{"goal", []ehl.Game{live, other}, []ehl.Game{goal, other}, []ehl.Game{live, goal}},
+		{"relocated", []ehl.Game{base}, []ehl.Game{relocated}, []ehl.Game{base, relocated}},
+		{"added", []ehl.Game{base}, []ehl.Game{base, other}, []ehl.Game{other}},
+		{"removed", []ehl.Game{base, other}, []ehl.Game{other}, []ehl.Game{base}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Affected(tt.previous, tt.current)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("expected %d games, got %d: %+v", len(tt.expected), len(got), got)
+			}
+			for i := range got {
+				if !sameGame(got[i], tt.expected[i]) {
+					t.Errorf("game %d: expected %+v, got %+v", i, tt.expected[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	dir := t.TempDir()
+	generated := time.Date(2025, 9, 1, 6, 0, 0, 0, time.UTC)
+
+	if err := WriteJSON(dir, nil, generated); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "changes.json"))
+	if err != nil {
+		t.Fatalf("failed to read changes.json: %v", err)
+	}
+
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("failed to decode changes.json: %v", err)
+	}
+	if !report.Generated.Equal(generated) || report.Changes == nil || len(report.Changes) != 0 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	if !strings.Contains(string(data), `"changes": []`) {
+		t.Errorf("expected empty changes array, got:\n%s", data)
+	}
+}
+
+func TestWriteSummary(t *testing.T) {
+	base := testGame("game-1")
+	moved := base
+	moved.StartTime = base.StartTime.Add(24 * time.Hour)
+	played := testGame("game-2")
+	played.StartTime = base.StartTime.Add(-24 * time.Hour)
+	played.State = ehl.StatePostGame
+	played.HomeTeam.Score = 3
+	played.AwayTeam.Score = 2
+	played.Decision = ehl.DecisionShootout
+	previousPlayed := played
+	previousPlayed.State = ehl.StatePreGame
+
+	tests := []struct {
+		name     string
+		changes  []Change
+		expected []string
+	}{
+		{
+			name:     "no changes",
+			expected: []string{"Ingen endringer i terminlisten."},
+		},
+		{
+			name:    "changes",
+			changes: Compare([]ehl.Game{base, previousPlayed}, []ehl.Game{moved, played}),
+			expected: []string{
+				"2 endringer i terminlisten:",
+				"- Resultat: Vålerenga 3 - 2 Storhamar (SO)",
+				"- Flyttet: Vålerenga vs Storhamar, fra 11.09.2025 19:00 til 12.09.2025 19:00",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteSummary(&buf, tt.changes, oslo); err != nil {
+				t.Fatalf("WriteSummary failed: %v", err)
+			}
+			if got := strings.TrimSpace(buf.String()); got != strings.Join(tt.expected, "\n") {
+				t.Errorf("expected:\n%s\ngot:\n%s", strings.Join(tt.expected, "\n"), got)
+			}
+		})
+	}
+}
+
+func TestHistoryAdd(t *testing.T) {
+	day1 := time.Date(2025, 9, 1, 6, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	day40 := day1.AddDate(0, 0, 40)
+
+	base := testGame("game-1")
+	moved := base
+	moved.StartTime = base.StartTime.Add(time.Hour)
+	movedAgain := moved
+	movedAgain.StartTime = moved.StartTime.Add(time.Hour)
+	other := testGame("game-2")
+
+	var h History
+	h = h.Add(Compare(nil, []ehl.Game{other}), day1, day1.AddDate(0, 0, -30))
+	h = h.Add(Compare([]ehl.Game{base}, []ehl.Game{moved}), day1, day1.AddDate(0, 0, -30))
+	if len(h) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(h))
+	}
+
+	// A second move replaces the first, keeping the ID
+	h = h.Add(Compare([]ehl.Game{moved}, []ehl.Game{movedAgain}), day2, day2.AddDate(0, 0, -30))
+	if len(h) != 2 {
+		t.Fatalf("expected 2 events after second move, got %d", len(h))
+	}
+	if h[0].ID() != "game-1/time_moved" || !h[0].Detected.Equal(day2) || !h[0].StartTime.Equal(movedAgain.StartTime) {
+		t.Errorf("expected latest move first, got %+v", h[0])
+	}
+
+	// Old events are dropped
+	h = h.Add(nil, day40, day40.AddDate(0, 0, -30))
+	if len(h) != 0 {
+		t.Errorf("expected old events to be dropped, got %+v", h)
+	}
+}
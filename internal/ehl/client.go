@@ -1,77 +1,241 @@
 package ehl
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
+	"time"
 )
 
 const (
 	// EHL API constants
-	DefaultBaseURL = "https://www.ehl.no"
-	SeriesUUID     = "qUu-397s1Dpwm"   // EliteHockey Ligaen
-	GameTypeUUID   = "qQ9-af37Ti40B"   // Regular season
+	DefaultBaseURL    = "https://www.ehl.no"
+	DefaultSeriesUUID = "qUu-397s1Dpwm" // EliteHockey Ligaen
+	GameTypeUUID      = "qQ9-af37Ti40B" // Regular season, used when no game types are discovered
+
+	// DefaultTimeout limits each request, including reading the response
+	DefaultTimeout = 30 * time.Second
+	// DefaultUserAgent identifies the requests to the EHL API
+	DefaultUserAgent = "hockeykalender (+https://github.com/thomasoddsund/hockeykalender)"
 )
 
-// Client is an HTTP client for the EHL API
+// DefaultSeries is the series published when no other series is configured
+var DefaultSeries = Series{Slug: "ehl", UUID: DefaultSeriesUUID, Name: "EHL"}
+
+// Client is an HTTP client for the EHL API, scoped to a single series
 type Client struct {
 	baseURL    string
+	seriesUUID string
 	httpClient *http.Client
+	userAgent  string
+	retry      RetryPolicy
+	sleep      func(ctx context.Context, d time.Duration) error // Waits between retries, replaced in tests
+}
+
+// ClientOption configures a Client
+type ClientOption func(*Client)
+
+// WithTimeout limits each request to d, 0 for no limit
+func WithTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.httpClient.Timeout = d
+	}
+}
+
+// WithRetryPolicy sets how failed requests are retried
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithUserAgent sets the User-Agent header of requests
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
 }
 
-// NewClient creates a new EHL API client
-func NewClient(baseURL string) *Client {
-	return &Client{
+// NewClient creates a new EHL API client for the default series
+func NewClient(baseURL string, opts ...ClientOption) *Client {
+	return NewSeriesClient(baseURL, DefaultSeriesUUID, opts...)
+}
+
+// NewSeriesClient creates a new API client for the given series
+func NewSeriesClient(baseURL, seriesUUID string, opts ...ClientOption) *Client {
+	c := &Client{
 		baseURL:    baseURL,
-		httpClient: &http.Client{},
+		seriesUUID: seriesUUID,
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+		userAgent:  DefaultUserAgent,
+		retry:      DefaultRetryPolicy(),
+		sleep:      sleep,
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
 // NewDefaultClient creates a client with the default EHL base URL
-func NewDefaultClient() *Client {
-	return NewClient(DefaultBaseURL)
+func NewDefaultClient(opts ...ClientOption) *Client {
+	return NewClient(DefaultBaseURL, opts...)
 }
 
-// seasonsResponse represents the API response for seasons
-type seasonsResponse struct {
-	Season []Season `json:"season"`
+// filterResponse represents the API response for the season/series/game type filter
+type filterResponse struct {
+	Season   []Season   `json:"season"`
+	GameType []GameType `json:"gameType"`
 }
 
-// FetchSeasons retrieves all available seasons from the API
-func (c *Client) FetchSeasons() ([]Season, error) {
-	endpoint := fmt.Sprintf("%s/api/sports-v2/season-series-game-types-filter?series=%s", c.baseURL, SeriesUUID)
+// fetchFilter retrieves the season/game type filter, optionally scoped to a season
+func (c *Client) fetchFilter(ctx context.Context, seasonUUID string) (filterResponse, error) {
+	params := url.Values{}
+	params.Set("series", c.seriesUUID)
+	if seasonUUID != "" {
+		params.Set("season", seasonUUID)
+	}
 
-	resp, err := c.httpClient.Get(endpoint)
+	endpoint := fmt.Sprintf("%s/api/sports-v2/season-series-game-types-filter?%s", c.baseURL, params.Encode())
+
+	var result filterResponse
+
+	body, err := c.get(ctx, endpoint)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch seasons: %w", err)
+		return result, fmt.Errorf("failed to fetch filter: %w", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	if err := json.Unmarshal(body, &result); err != nil {
+		return result, fmt.Errorf("failed to decode filter response: %w", err)
 	}
 
-	var result seasonsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode seasons response: %w", err)
+	return result, nil
+}
+
+// FetchSeasons retrieves all available seasons from the API
+func (c *Client) FetchSeasons(ctx context.Context) ([]Season, error) {
+	result, err := c.fetchFilter(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch seasons: %w", err)
 	}
 
 	return result.Season, nil
 }
 
-// GetCurrentSeason returns the most recent season (first in the list)
-func (c *Client) GetCurrentSeason() (Season, error) {
-	seasons, err := c.FetchSeasons()
+// FetchGameTypes retrieves the game types (regular season, playoffs, ...) available in a season.
+// Falls back to the regular season if the API does not list any.
+func (c *Client) FetchGameTypes(ctx context.Context, seasonUUID string) ([]GameType, error) {
+	result, err := c.fetchFilter(ctx, seasonUUID)
 	if err != nil {
-		return Season{}, err
+		return nil, fmt.Errorf("failed to fetch game types: %w", err)
+	}
+
+	if len(result.GameType) == 0 {
+		return []GameType{{UUID: GameTypeUUID}}, nil
+	}
+
+	return result.GameType, nil
+}
+
+// GetCurrentSeason returns the season being played right now, see CurrentSeason
+func (c *Client) GetCurrentSeason(ctx context.Context) (Season, error) {
+	season, _, err := c.CurrentSeason(ctx, time.Now())
+	return season, err
+}
+
+// seasonGrace keeps a season current for a short while after its last game,
+// so the final results stay visible before switching to the next season
+const seasonGrace = 7 * 24 * time.Hour
+
+// CurrentSeason picks the season to publish based on game dates rather than API order:
+// the season whose games span now, otherwise the next upcoming season with games,
+// otherwise the most recently finished one. The season's games are returned as well.
+// If no season has any games, the newest season is returned.
+func (c *Client) CurrentSeason(ctx context.Context, now time.Time) (Season, []Game, error) {
+	seasons, err := c.FetchSeasons(ctx)
+	if err != nil {
+		return Season{}, nil, err
 	}
 
 	if len(seasons) == 0 {
-		return Season{}, fmt.Errorf("no seasons found")
+		return Season{}, nil, fmt.Errorf("no seasons found")
 	}
 
-	return seasons[0], nil
+	// Newest first, "2025/2026" sorts after "2024/2025"
+	sort.SliceStable(seasons, func(i, j int) bool {
+		return seasons[i].Name > seasons[j].Name
+	})
+
+	var upcoming *Season
+	var upcomingGames []Game
+
+	for i := range seasons {
+		games, err := c.FetchGames(ctx, seasons[i].UUID)
+		if err != nil {
+			return Season{}, nil, err
+		}
+		if len(games) == 0 {
+			continue
+		}
+
+		first, last := SeasonSpan(games)
+		switch {
+		case now.Before(first):
+			// Seasons are newest first, so this is the nearest upcoming season so far
+			upcoming, upcomingGames = &seasons[i], games
+		case now.After(last.Add(seasonGrace)):
+			// Finished; all remaining seasons are older
+			if upcoming != nil {
+				return *upcoming, upcomingGames, nil
+			}
+			return seasons[i], games, nil
+		default:
+			return seasons[i], games, nil
+		}
+	}
+
+	if upcoming != nil {
+		return *upcoming, upcomingGames, nil
+	}
+
+	return seasons[0], nil, nil
+}
+
+// PreviousSeason returns the season before the given one, and false if it is the oldest
+func (c *Client) PreviousSeason(ctx context.Context, season Season) (Season, bool, error) {
+	seasons, err := c.FetchSeasons(ctx)
+	if err != nil {
+		return Season{}, false, err
+	}
+
+	var previous Season
+	found := false
+	for _, s := range seasons {
+		if s.Name < season.Name && (!found || s.Name > previous.Name) {
+			previous, found = s, true
+		}
+	}
+
+	return previous, found, nil
+}
+
+// FindSeason returns the season matching a name (e.g. "2025/2026"), slug (e.g. "2025-2026") or UUID
+func (c *Client) FindSeason(ctx context.Context, nameOrUUID string) (Season, error) {
+	seasons, err := c.FetchSeasons(ctx)
+	if err != nil {
+		return Season{}, err
+	}
+
+	for _, season := range seasons {
+		if season.UUID == nameOrUUID || season.Name == nameOrUUID || season.Slug() == nameOrUUID {
+			return season, nil
+		}
+	}
+
+	return Season{}, fmt.Errorf("season %q not found", nameOrUUID)
 }
 
 // gamesResponse wraps the API response for games
@@ -79,32 +243,63 @@ type gamesResponse struct {
 	GameInfo []Game `json:"gameInfo"`
 }
 
-// FetchGames retrieves all games for a given season
-func (c *Client) FetchGames(seasonUUID string) ([]Game, error) {
+// FetchGames retrieves all games of every game type for a given season, sorted by start time
+func (c *Client) FetchGames(ctx context.Context, seasonUUID string) ([]Game, error) {
+	gameTypes, err := c.FetchGameTypes(ctx, seasonUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var games []Game
+
+	for _, gameType := range gameTypes {
+		typeGames, err := c.FetchGamesOfType(ctx, seasonUUID, gameType)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, game := range typeGames {
+			if seen[game.UUID] {
+				continue
+			}
+			seen[game.UUID] = true
+			games = append(games, game)
+		}
+	}
+
+	sort.SliceStable(games, func(i, j int) bool {
+		return games[i].StartTime.Before(games[j].StartTime)
+	})
+
+	return games, nil
+}
+
+// FetchGamesOfType retrieves the games of a single game type for a given season
+func (c *Client) FetchGamesOfType(ctx context.Context, seasonUUID string, gameType GameType) ([]Game, error) {
 	params := url.Values{}
 	params.Set("seasonUuid", seasonUUID)
-	params.Set("seriesUuid", SeriesUUID)
-	params.Set("gameTypeUuid", GameTypeUUID)
+	params.Set("seriesUuid", c.seriesUUID)
+	params.Set("gameTypeUuid", gameType.UUID)
 	params.Set("gamePlace", "all")
 	params.Set("played", "all")
 
 	endpoint := fmt.Sprintf("%s/api/sports-v2/game-schedule?%s", c.baseURL, params.Encode())
 
-	resp, err := c.httpClient.Get(endpoint)
+	body, err := c.get(ctx, endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch games: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
 
 	var result gamesResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := json.Unmarshal(body, &result); err != nil {
 		return nil, fmt.Errorf("failed to decode games response: %w", err)
 	}
 
+	for i := range result.GameInfo {
+		result.GameInfo[i].GameType = gameType
+	}
+
 	return result.GameInfo, nil
 }
 
@@ -128,3 +323,32 @@ func ExtractTeams(games []Game) []Team {
 
 	return teams
 }
+
+// ExtractVenues returns the unique venues of a list of games, sorted by name.
+// Venues without a UUID are identified by name.
+func ExtractVenues(games []Game) []Venue {
+	seen := make(map[string]Venue)
+
+	for _, game := range games {
+		key := game.Venue.UUID
+		if key == "" {
+			key = game.Venue.Name
+		}
+		if key == "" {
+			continue
+		}
+		if _, exists := seen[key]; !exists {
+			seen[key] = game.Venue
+		}
+	}
+
+	venues := make([]Venue, 0, len(seen))
+	for _, venue := range seen {
+		venues = append(venues, venue)
+	}
+	sort.Slice(venues, func(i, j int) bool {
+		return venues[i].Name < venues[j].Name
+	})
+
+	return venues
+}
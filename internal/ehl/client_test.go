@@ -1,9 +1,11 @@
 package ehl
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 const testSeasonsResponse = `{
@@ -11,6 +13,33 @@ const testSeasonsResponse = `{
 		{"uuid": "bir2zwf4qa", "names": [{"language": "no", "translation": "2025/2026"}]},
 		{"uuid": "qec-2Ioo12KN8s", "names": [{"language": "no", "translation": "2024/2025"}]},
 		{"uuid": "qd0-6kFP17sVG", "names": [{"language": "no", "translation": "2023/2024"}]}
+	],
+	"gameType": [
+		{"uuid": "qQ9-af37Ti40B", "names": [{"language": "no", "translation": "Serie"}]},
+		{"uuid": "qQ9-playoffs01", "names": [{"language": "no", "translation": "Sluttspill"}]}
+	]
+}`
+
+const testPlayoffGamesResponse = `{
+	"gameInfo": [
+		{
+			"uuid": "cqdidacsop",
+			"rawStartDateTime": "2025-09-10T17:00:00.000Z",
+			"state": "pre-game",
+			"homeTeamInfo": {
+				"uuid": "qQ0-A0eF1CWG5",
+				"code": "VIF",
+				"names": {"full": "Vålerenga Ishockey Elite", "short": "Vålerenga"},
+				"score": 0
+			},
+			"awayTeamInfo": {
+				"uuid": "qQ0-8E8X1CsEP",
+				"code": "STH",
+				"names": {"full": "Storhamar Ishockey Elite", "short": "Storhamar"},
+				"score": 0
+			},
+			"venueInfo": {"uuid": "venue-123", "name": "Jordal Amfi"}
+		}
 	]
 }`
 
@@ -66,7 +95,7 @@ func TestFetchSeasons(t *testing.T) {
 	defer server.Close()
 
 	client := NewClient(server.URL)
-	seasons, err := client.FetchSeasons()
+	seasons, err := client.FetchSeasons(context.Background())
 	if err != nil {
 		t.Fatalf("FetchSeasons failed: %v", err)
 	}
@@ -91,7 +120,7 @@ func TestGetCurrentSeason(t *testing.T) {
 	defer server.Close()
 
 	client := NewClient(server.URL)
-	season, err := client.GetCurrentSeason()
+	season, err := client.GetCurrentSeason(context.Background())
 	if err != nil {
 		t.Fatalf("GetCurrentSeason failed: %v", err)
 	}
@@ -101,24 +130,46 @@ func TestGetCurrentSeason(t *testing.T) {
 	}
 }
 
-func TestFetchGames(t *testing.T) {
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path != "/api/sports-v2/game-schedule" {
-			t.Errorf("unexpected path: %s", r.URL.Path)
-		}
+// newTestServer serves the filter endpoint and regular season games,
+// plus playoff games if withPlayoffs is set
+func newTestServer(t *testing.T, withPlayoffs bool) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
 
-		seasonUUID := r.URL.Query().Get("seasonUuid")
-		if seasonUUID != "bir2zwf4qa" {
-			t.Errorf("expected seasonUuid 'bir2zwf4qa', got '%s'", seasonUUID)
-		}
+		switch r.URL.Path {
+		case "/api/sports-v2/season-series-game-types-filter":
+			w.Write([]byte(testSeasonsResponse))
+		case "/api/sports-v2/game-schedule":
+			seasonUUID := r.URL.Query().Get("seasonUuid")
+			if seasonUUID != "bir2zwf4qa" {
+				t.Errorf("expected seasonUuid 'bir2zwf4qa', got '%s'", seasonUUID)
+			}
 
-		w.Header().Set("Content-Type", "application/json")
-		w.Write([]byte(testGamesResponse))
+			switch r.URL.Query().Get("gameTypeUuid") {
+			case "qQ9-af37Ti40B":
+				w.Write([]byte(testGamesResponse))
+			case "qQ9-playoffs01":
+				if withPlayoffs {
+					w.Write([]byte(testPlayoffGamesResponse))
+				} else {
+					w.Write([]byte(`{"gameInfo": []}`))
+				}
+			default:
+				t.Errorf("unexpected gameTypeUuid: %s", r.URL.Query().Get("gameTypeUuid"))
+			}
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
 	}))
+}
+
+func TestFetchGames(t *testing.T) {
+	server := newTestServer(t, false)
 	defer server.Close()
 
 	client := NewClient(server.URL)
-	games, err := client.FetchGames("bir2zwf4qa")
+	games, err := client.FetchGames(context.Background(), "bir2zwf4qa")
 	if err != nil {
 		t.Fatalf("FetchGames failed: %v", err)
 	}
@@ -133,20 +184,88 @@ func TestFetchGames(t *testing.T) {
 	if games[0].HomeTeam.ShortName != "Vålerenga" {
 		t.Errorf("expected home team 'Vålerenga', got '%s'", games[0].HomeTeam.ShortName)
 	}
-	if games[0].Venue != "Jordal Amfi" {
-		t.Errorf("expected venue 'Jordal Amfi', got '%s'", games[0].Venue)
+	if games[0].Venue != (Venue{UUID: "venue-123", Name: "Jordal Amfi"}) {
+		t.Errorf("expected venue 'Jordal Amfi' (venue-123), got %+v", games[0].Venue)
+	}
+	if games[0].GameType.Name != "Serie" {
+		t.Errorf("expected game type 'Serie', got '%s'", games[0].GameType.Name)
 	}
 }
 
-func TestExtractTeams(t *testing.T) {
+func TestFetchGames_AllGameTypes(t *testing.T) {
+	server := newTestServer(t, true)
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	games, err := client.FetchGames(context.Background(), "bir2zwf4qa")
+	if err != nil {
+		t.Fatalf("FetchGames failed: %v", err)
+	}
+
+	if len(games) != 3 {
+		t.Fatalf("expected 3 games, got %d", len(games))
+	}
+
+	// Games from all types are merged and sorted by start time
+	if games[0].UUID != "cqdidacsop" {
+		t.Errorf("expected first game UUID 'cqdidacsop', got '%s'", games[0].UUID)
+	}
+	if games[0].GameType.Name != "Sluttspill" {
+		t.Errorf("expected game type 'Sluttspill', got '%s'", games[0].GameType.Name)
+	}
+	if games[1].GameType.UUID != GameTypeUUID {
+		t.Errorf("expected game type UUID '%s', got '%s'", GameTypeUUID, games[1].GameType.UUID)
+	}
+}
+
+func TestFetchGameTypes(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("season") != "bir2zwf4qa" {
+			t.Errorf("expected season 'bir2zwf4qa', got '%s'", r.URL.Query().Get("season"))
+		}
 		w.Header().Set("Content-Type", "application/json")
-		w.Write([]byte(testGamesResponse))
+		w.Write([]byte(testSeasonsResponse))
 	}))
 	defer server.Close()
 
 	client := NewClient(server.URL)
-	games, _ := client.FetchGames("bir2zwf4qa")
+	gameTypes, err := client.FetchGameTypes(context.Background(), "bir2zwf4qa")
+	if err != nil {
+		t.Fatalf("FetchGameTypes failed: %v", err)
+	}
+
+	if len(gameTypes) != 2 {
+		t.Fatalf("expected 2 game types, got %d", len(gameTypes))
+	}
+	if gameTypes[1].Name != "Sluttspill" {
+		t.Errorf("expected second game type 'Sluttspill', got '%s'", gameTypes[1].Name)
+	}
+}
+
+func TestFetchGameTypes_Fallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"season": []}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	gameTypes, err := client.FetchGameTypes(context.Background(), "bir2zwf4qa")
+	if err != nil {
+		t.Fatalf("FetchGameTypes failed: %v", err)
+	}
+
+	if len(gameTypes) != 1 || gameTypes[0].UUID != GameTypeUUID {
+		t.Errorf("expected fallback to regular season, got %v", gameTypes)
+	}
+}
+
+func TestExtractTeams(t *testing.T) {
+	server := newTestServer(t, false)
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	games, _ := client.FetchGames(context.Background(), "bir2zwf4qa")
 
 	teams := ExtractTeams(games)
 
@@ -167,3 +286,155 @@ func TestExtractTeams(t *testing.T) {
 		t.Error("expected Storhamar in teams")
 	}
 }
+
+func TestExtractVenues(t *testing.T) {
+	jordal := Venue{UUID: "venue-123", Name: "Jordal Amfi"}
+	cc := Venue{UUID: "venue-456", Name: "CC Amfi"}
+
+	games := []Game{
+		{UUID: "game-1", Venue: jordal},
+		{UUID: "game-2", Venue: cc},
+		{UUID: "game-3", Venue: jordal},
+		{UUID: "game-4", Venue: Venue{Name: "Nye Hamar OL-amfi"}},
+		{UUID: "game-5"},
+	}
+
+	venues := ExtractVenues(games)
+
+	expected := []Venue{cc, jordal, {Name: "Nye Hamar OL-amfi"}}
+	if len(venues) != len(expected) {
+		t.Fatalf("expected %d venues, got %+v", len(expected), venues)
+	}
+	for i := range expected {
+		if venues[i] != expected[i] {
+			t.Errorf("venue %d: expected %+v, got %+v", i, expected[i], venues[i])
+		}
+	}
+}
+
+func TestNewSeriesClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("series") != "series-women" {
+			t.Errorf("expected series 'series-women', got '%s'", r.URL.Query().Get("series"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(testSeasonsResponse))
+	}))
+	defer server.Close()
+
+	client := NewSeriesClient(server.URL, "series-women")
+	if _, err := client.FetchSeasons(context.Background()); err != nil {
+		t.Fatalf("FetchSeasons failed: %v", err)
+	}
+}
+
+// seasonGame returns a minimal game-schedule entry starting at the given time
+func seasonGame(uuid, start string) string {
+	return `{"uuid": "` + uuid + `", "rawStartDateTime": "` + start + `", "state": "pre-game",
+		"homeTeamInfo": {"uuid": "h", "names": {"short": "Vålerenga"}},
+		"awayTeamInfo": {"uuid": "a", "names": {"short": "Storhamar"}},
+		"venueInfo": {"uuid": "v", "name": "Jordal Amfi"}}`
+}
+
+func newSeasonsTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	gamesBySeason := map[string]string{
+		"season-2627": `{"gameInfo": []}`,
+		"season-2526": `{"gameInfo": [` + seasonGame("g3", "2025-09-11T17:00:00.000Z") + `,` + seasonGame("g4", "2026-03-20T17:00:00.000Z") + `]}`,
+		"season-2425": `{"gameInfo": [` + seasonGame("g1", "2024-09-11T17:00:00.000Z") + `,` + seasonGame("g2", "2025-03-20T17:00:00.000Z") + `]}`,
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/sports-v2/season-series-game-types-filter":
+			// Deliberately not newest first
+			w.Write([]byte(`{"season": [
+				{"uuid": "season-2425", "names": [{"language": "no", "translation": "2024/2025"}]},
+				{"uuid": "season-2627", "names": [{"language": "no", "translation": "2026/2027"}]},
+				{"uuid": "season-2526", "names": [{"language": "no", "translation": "2025/2026"}]}
+			]}`))
+		case "/api/sports-v2/game-schedule":
+			w.Write([]byte(gamesBySeason[r.URL.Query().Get("seasonUuid")]))
+		}
+	}))
+}
+
+func TestCurrentSeason(t *testing.T) {
+	server := newSeasonsTestServer(t)
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		expected string
+	}{
+		{"during season", time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC), "season-2526"},
+		{"before first game", time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC), "season-2526"},
+		{"just after last game", time.Date(2025, 3, 22, 12, 0, 0, 0, time.UTC), "season-2425"},
+		{"after all seasons", time.Date(2026, 8, 1, 12, 0, 0, 0, time.UTC), "season-2526"},
+		{"before all seasons", time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), "season-2425"},
+	}
+
+	client := NewClient(server.URL)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			season, games, err := client.CurrentSeason(context.Background(), tt.now)
+			if err != nil {
+				t.Fatalf("CurrentSeason failed: %v", err)
+			}
+			if season.UUID != tt.expected {
+				t.Errorf("expected season %s, got %s", tt.expected, season.UUID)
+			}
+			if len(games) != 2 {
+				t.Errorf("expected 2 games, got %d", len(games))
+			}
+		})
+	}
+}
+
+func TestFindSeason(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(testSeasonsResponse))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+
+	for _, query := range []string{"2024/2025", "2024-2025", "qec-2Ioo12KN8s"} {
+		season, err := client.FindSeason(context.Background(), query)
+		if err != nil {
+			t.Fatalf("FindSeason(%q) failed: %v", query, err)
+		}
+		if season.UUID != "qec-2Ioo12KN8s" {
+			t.Errorf("FindSeason(%q) = %s, want qec-2Ioo12KN8s", query, season.UUID)
+		}
+	}
+
+	if _, err := client.FindSeason(context.Background(), "1999/2000"); err == nil {
+		t.Error("expected error for unknown season")
+	}
+}
+
+func TestPreviousSeason(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(testSeasonsResponse))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+
+	previous, ok, err := client.PreviousSeason(context.Background(), Season{Name: "2025/2026"})
+	if err != nil {
+		t.Fatalf("PreviousSeason failed: %v", err)
+	}
+	if !ok || previous.Name != "2024/2025" {
+		t.Errorf("expected 2024/2025, got %q (ok=%v)", previous.Name, ok)
+	}
+
+	if _, ok, _ := client.PreviousSeason(context.Background(), Season{Name: "2023/2024"}); ok {
+		t.Error("expected no season before the oldest one")
+	}
+}
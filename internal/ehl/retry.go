@@ -0,0 +1,149 @@
+package ehl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy decides how failed requests are retried
+type RetryPolicy struct {
+	Retries   int           // Retries after the first attempt, 0 to fail at once
+	BaseDelay time.Duration // Delay before the first retry, doubled for every retry
+	MaxDelay  time.Duration // Longest delay between attempts, also the longest Retry-After honoured
+}
+
+// DefaultRetryPolicy retries 3 times, after about 1, 2 and 4 seconds
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{Retries: 3, BaseDelay: time.Second, MaxDelay: 30 * time.Second}
+}
+
+// delay returns the backoff before retry number attempt (0 for the first retry),
+// with jitter so that clients failing together do not retry together
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 0; i < attempt && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	d = min(d, p.MaxDelay)
+	if d <= 0 {
+		return 0
+	}
+
+	// Between half and all of the backoff
+	return d/2 + rand.N(d/2+1)
+}
+
+// statusError is an unexpected HTTP response
+type statusError struct {
+	StatusCode int
+	RetryAfter time.Duration // From the Retry-After header, 0 if absent
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+}
+
+// temporary reports whether the request may succeed if retried
+func (e *statusError) temporary() bool {
+	return e.StatusCode >= 500 || e.StatusCode == http.StatusTooManyRequests
+}
+
+// get fetches endpoint and returns the response body. Server errors, rate limiting and
+// network errors are retried with exponential backoff, waiting at least as long as the
+// server asks for with Retry-After.
+func (c *Client) get(ctx context.Context, endpoint string) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		body, err := c.getOnce(ctx, endpoint)
+		if err == nil {
+			return body, nil
+		}
+
+		var status *statusError
+		isStatus := errors.As(err, &status)
+		switch {
+		case ctx.Err() != nil:
+			return nil, err
+		case isStatus && !status.temporary():
+			return nil, err
+		case attempt >= c.retry.Retries:
+			if attempt > 0 {
+				return nil, fmt.Errorf("%w (after %d attempts)", err, attempt+1)
+			}
+			return nil, err
+		}
+
+		delay := c.retry.delay(attempt)
+		if isStatus && status.RetryAfter > delay {
+			if status.RetryAfter > c.retry.MaxDelay {
+				return nil, fmt.Errorf("%w (retry after %s)", err, status.RetryAfter)
+			}
+			delay = status.RetryAfter
+		}
+
+		log.Printf("Request failed, retrying in %s: %v", delay.Round(time.Millisecond), err)
+		if err := c.sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// getOnce makes a single request to endpoint
+func (c *Client) getOnce(ctx context.Context, endpoint string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &statusError{StatusCode: resp.StatusCode, RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return body, nil
+}
+
+// parseRetryAfter parses a Retry-After header, either seconds or an HTTP date.
+// It returns 0 if the header is absent, invalid or in the past.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(0, time.Duration(seconds)*time.Second)
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(0, t.Sub(now))
+	}
+	return 0
+}
+
+// sleep waits for d, or until ctx is cancelled
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,224 @@
+package ehl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyServer answers the nth request with the nth handler, repeating the last one
+type flakyServer struct {
+	*httptest.Server
+	mu         sync.Mutex
+	requests   int
+	userAgents []string
+}
+
+func newFlakyServer(t *testing.T, handlers ...http.HandlerFunc) *flakyServer {
+	t.Helper()
+	s := &flakyServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		handler := handlers[min(s.requests, len(handlers)-1)]
+		s.requests++
+		s.userAgents = append(s.userAgents, r.UserAgent())
+		s.mu.Unlock()
+		handler(w, r)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// count returns the number of requests so far
+func (s *flakyServer) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func status(code int, retryAfter string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if retryAfter != "" {
+			w.Header().Set("Retry-After", retryAfter)
+		}
+		w.WriteHeader(code)
+	}
+}
+
+func seasons(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(testSeasonsResponse))
+}
+
+// dropConnection closes the connection without a response
+func dropConnection(w http.ResponseWriter, r *http.Request) {
+	conn, _, err := w.(http.Hijacker).Hijack()
+	if err == nil {
+		conn.Close()
+	}
+}
+
+// newRetryClient returns a client that records the waits between retries instead of sleeping
+func newRetryClient(url string, waits *[]time.Duration, opts ...ClientOption) *Client {
+	client := NewClient(url, opts...)
+	client.sleep = func(ctx context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		return ctx.Err()
+	}
+	return client
+}
+
+func TestClient_Retries(t *testing.T) {
+	tests := []struct {
+		name      string
+		handlers  []http.HandlerFunc
+		wantErr   string
+		requests  int
+		wantWaits []time.Duration // Exact waits, nil to only check the count
+		waits     int
+	}{
+		{"success", []http.HandlerFunc{seasons}, "", 1, nil, 0},
+		{"server errors", []http.HandlerFunc{status(http.StatusBadGateway, ""), status(http.StatusServiceUnavailable, ""), seasons}, "", 3, nil, 2},
+		{"network error", []http.HandlerFunc{dropConnection, seasons}, "", 2, nil, 1},
+		{"retry after seconds", []http.HandlerFunc{status(http.StatusTooManyRequests, "20"), seasons}, "", 2, []time.Duration{20 * time.Second}, 1},
+		{"retry after too long", []http.HandlerFunc{status(http.StatusServiceUnavailable, "3600"), seasons}, "retry after 1h0m0s", 1, nil, 0},
+		{"client error", []http.HandlerFunc{status(http.StatusNotFound, ""), seasons}, "unexpected status code: 404", 1, nil, 0},
+		{"gives up", []http.HandlerFunc{status(http.StatusInternalServerError, "")}, "unexpected status code: 500 (after 4 attempts)", 4, nil, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFlakyServer(t, tt.handlers...)
+			var waits []time.Duration
+			client := newRetryClient(server.URL, &waits)
+
+			result, err := client.FetchSeasons(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+			} else if err != nil || len(result) != 3 {
+				t.Errorf("FetchSeasons() = %d seasons, %v", len(result), err)
+			}
+
+			if server.count() != tt.requests {
+				t.Errorf("expected %d requests, got %d", tt.requests, server.count())
+			}
+			if len(waits) != tt.waits {
+				t.Errorf("expected %d waits, got %v", tt.waits, waits)
+			}
+			for i, want := range tt.wantWaits {
+				if i < len(waits) && waits[i] != want {
+					t.Errorf("wait %d: expected %s, got %s", i, want, waits[i])
+				}
+			}
+		})
+	}
+}
+
+func TestClient_Timeout(t *testing.T) {
+	slow := func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}
+	server := newFlakyServer(t, slow, seasons)
+
+	var waits []time.Duration
+	client := newRetryClient(server.URL, &waits, WithTimeout(50*time.Millisecond))
+
+	if _, err := client.FetchSeasons(context.Background()); err != nil {
+		t.Fatalf("expected the timed out request to be retried, got %v", err)
+	}
+	if server.count() != 2 {
+		t.Errorf("expected 2 requests, got %d", server.count())
+	}
+}
+
+func TestClient_Cancel(t *testing.T) {
+	server := newFlakyServer(t, status(http.StatusInternalServerError, ""))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client := NewClient(server.URL)
+	client.sleep = func(ctx context.Context, d time.Duration) error {
+		cancel()
+		return sleep(ctx, d)
+	}
+
+	if _, err := client.FetchSeasons(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if server.count() != 1 {
+		t.Errorf("expected no retries after cancel, got %d requests", server.count())
+	}
+}
+
+func TestClient_UserAgent(t *testing.T) {
+	server := newFlakyServer(t, seasons)
+
+	if _, err := NewClient(server.URL).FetchSeasons(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewClient(server.URL, WithUserAgent("test/1.0")).FetchSeasons(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{DefaultUserAgent, "test/1.0"}
+	for i, want := range expected {
+		if server.userAgents[i] != want {
+			t.Errorf("request %d: expected User-Agent %q, got %q", i, want, server.userAgents[i])
+		}
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{Retries: 10, BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+
+	tests := []struct {
+		attempt int
+		backoff time.Duration
+	}{
+		{0, time.Second},
+		{1, 2 * time.Second},
+		{3, 8 * time.Second},
+		{4, 10 * time.Second}, // Capped
+		{60, 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		for range 20 {
+			// Jitter keeps the delay between half and all of the backoff
+			if d := policy.delay(tt.attempt); d < tt.backoff/2 || d > tt.backoff {
+				t.Errorf("delay(%d) = %s, want between %s and %s", tt.attempt, d, tt.backoff/2, tt.backoff)
+			}
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{"", 0},
+		{"120", 2 * time.Minute},
+		{"-5", 0},
+		{"Mon, 01 Sep 2025 12:00:30 GMT", 30 * time.Second},
+		{"Mon, 01 Sep 2025 11:00:00 GMT", 0},
+		{"soon", 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.expected {
+			t.Errorf("parseRetryAfter(%q) = %s, want %s", tt.value, got, tt.expected)
+		}
+	}
+}
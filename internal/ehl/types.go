@@ -3,6 +3,7 @@ package ehl
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 	"unicode"
@@ -12,6 +13,32 @@ import (
 	"golang.org/x/text/unicode/norm"
 )
 
+// Series represents a league or series on the sports-v2 platform
+type Series struct {
+	Slug string // Output namespace and combined feed name, e.g. "ehl"
+	UUID string
+	Name string // Display name used in calendar names, e.g. "EHL"
+}
+
+// ParseSeries parses a series spec of the form "slug=uuid" or "slug=uuid:Name".
+// The name defaults to the upper-cased slug.
+func ParseSeries(spec string) (Series, error) {
+	slug, rest, ok := strings.Cut(spec, "=")
+	if !ok || slug == "" || rest == "" {
+		return Series{}, fmt.Errorf("invalid series %q: expected slug=uuid[:name]", spec)
+	}
+
+	uuid, name, _ := strings.Cut(rest, ":")
+	if uuid == "" {
+		return Series{}, fmt.Errorf("invalid series %q: missing uuid", spec)
+	}
+	if name == "" {
+		name = strings.ToUpper(slug)
+	}
+
+	return Series{Slug: slug, UUID: uuid, Name: name}, nil
+}
+
 // Season represents an EHL season
 type Season struct {
 	UUID string `json:"uuid"`
@@ -24,31 +51,78 @@ type Translation struct {
 	Translation string `json:"translation"`
 }
 
-// seasonJSON is used for unmarshaling the nested JSON structure
-type seasonJSON struct {
+// namedJSON is used for unmarshaling entities with a UUID and localized names
+type namedJSON struct {
 	UUID  string        `json:"uuid"`
 	Names []Translation `json:"names"`
 }
 
+// localizedName returns the Norwegian name, falling back to the first available
+func localizedName(names []Translation) string {
+	var name string
+	for _, n := range names {
+		if n.Language == "no" {
+			return n.Translation
+		}
+		if name == "" {
+			name = n.Translation
+		}
+	}
+	return name
+}
+
 func (s *Season) UnmarshalJSON(data []byte) error {
-	var sj seasonJSON
+	var sj namedJSON
 	if err := json.Unmarshal(data, &sj); err != nil {
 		return err
 	}
 	s.UUID = sj.UUID
-	// Get Norwegian name, fallback to first available
-	for _, n := range sj.Names {
-		if n.Language == "no" {
-			s.Name = n.Translation
-			break
-		}
-		if s.Name == "" {
-			s.Name = n.Translation
-		}
+	s.Name = localizedName(sj.Names)
+	return nil
+}
+
+// Slug returns a URL-friendly version of the season name, e.g. "2025/2026" → "2025-2026"
+func (s *Season) Slug() string {
+	return strings.NewReplacer("/", "-", " ", "-").Replace(strings.ToLower(s.Name))
+}
+
+// GameType represents a kind of game within a season (regular season, playoffs, qualification, ...)
+type GameType struct {
+	UUID string `json:"uuid"`
+	Name string `json:"-"`
+}
+
+// regularSeasonNames are the names the API gives the regular season game type, lower-cased
+var regularSeasonNames = map[string]bool{
+	"serie":          true,
+	"grunnserie":     true,
+	"seriespill":     true,
+	"regular season": true,
+}
+
+// IsRegularSeason reports whether the game type is the regular season, by UUID in EHL or by name in any series
+func (gt GameType) IsRegularSeason() bool {
+	return gt.UUID == GameTypeUUID || regularSeasonNames[strings.ToLower(strings.TrimSpace(gt.Name))]
+}
+
+func (gt *GameType) UnmarshalJSON(data []byte) error {
+	var gj namedJSON
+	if err := json.Unmarshal(data, &gj); err != nil {
+		return err
 	}
+	gt.UUID = gj.UUID
+	gt.Name = localizedName(gj.Names)
 	return nil
 }
 
+// MarshalJSON implements json.Marshaler for GameType, mirroring the API format
+func (gt GameType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(namedJSON{
+		UUID:  gt.UUID,
+		Names: []Translation{{Language: "no", Translation: gt.Name}},
+	})
+}
+
 // Team represents a team in a game
 type Team struct {
 	UUID      string `json:"uuid"`
@@ -112,7 +186,7 @@ type teamMarshalJSON struct {
 	Icon  string `json:"icon"`
 }
 
-// MarshalJSON implements json.Marshaler for Team (used in tests)
+// MarshalJSON implements json.Marshaler for Team (used in tests and the season cache)
 func (t Team) MarshalJSON() ([]byte, error) {
 	return json.Marshal(teamMarshalJSON{
 		UUID:  t.UUID,
@@ -131,7 +205,12 @@ func (t Team) MarshalJSON() ([]byte, error) {
 
 // Slug returns a URL-friendly version of the team's short name
 func (t *Team) Slug() string {
-	name := strings.ToLower(t.ShortName)
+	return slugify(t.ShortName)
+}
+
+// slugify lower-cases a name, strips Norwegian letters and diacritics and replaces spaces with hyphens
+func slugify(name string) string {
+	name = strings.ToLower(name)
 
 	// Replace Norwegian special characters that don't decompose with NFD
 	replacer := strings.NewReplacer(
@@ -150,34 +229,142 @@ func (t *Team) Slug() string {
 	return result
 }
 
+// Venue is the arena a game is played at
+type Venue struct {
+	UUID string `json:"uuid"`
+	Name string `json:"name"`
+}
+
+// Slug returns a URL-friendly version of the venue name, e.g. "CC Amfi, Hamar" → "cc-amfi-hamar"
+func (v *Venue) Slug() string {
+	var sb strings.Builder
+	for _, part := range strings.FieldsFunc(slugify(v.Name), func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+	}) {
+		if sb.Len() > 0 {
+			sb.WriteByte('-')
+		}
+		sb.WriteString(part)
+	}
+	return sb.String()
+}
+
+// GameState is the state of a game as reported by the API
+type GameState string
+
+// Game states reported by the API, plus StateRemoved for games that
+// disappeared from the schedule after being published
+const (
+	StatePreGame   GameState = "pre-game"
+	StateLive      GameState = "live"
+	StatePostGame  GameState = "post-game"
+	StateCancelled GameState = "cancelled"
+	StatePostponed GameState = "postponed"
+	StateRemoved   GameState = "removed"
+)
+
+// Started reports whether the game has a score, i.e. is live or finished
+func (s GameState) Started() bool {
+	return s == StateLive || s == StatePostGame
+}
+
+// Decision is how a finished game was decided
+type Decision string
+
+const (
+	DecisionRegulation Decision = ""
+	DecisionOvertime   Decision = "overtime"
+	DecisionShootout   Decision = "shootout"
+)
+
+// ParseDecision maps the API's decision of a game, e.g. "OT" or "shootout", to a Decision.
+// Anything unrecognised is treated as regulation time.
+func ParseDecision(s string) Decision {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "ot", "overtime":
+		return DecisionOvertime
+	case "so", "shootout":
+		return DecisionShootout
+	}
+	return DecisionRegulation
+}
+
+// minDecidedGames is how many finished games a season needs before CheckDecisions expects
+// some of them to have been decided in overtime or a shootout, as a good share always are
+const minDecidedGames = 30
+
+// CheckDecisions returns an error if the finished games look like the API reports overtime
+// and shootout results in a way ParseDecision does not understand: none of many finished
+// games has a decision, or a finished game has a level score and so no winner. Such results
+// would otherwise show up as regulation results, and in the table with the wrong points.
+func CheckDecisions(games []Game) error {
+	finished, decided, level := 0, 0, 0
+	for _, game := range games {
+		if game.State != StatePostGame {
+			continue
+		}
+		finished++
+		if game.Decision != DecisionRegulation {
+			decided++
+		}
+		if game.HomeTeam.Score == game.AwayTeam.Score {
+			level++
+		}
+	}
+
+	switch {
+	case level > 0:
+		return fmt.Errorf("%d of %d finished games have a level score, so their winner is unknown", level, finished)
+	case finished >= minDecidedGames && decided == 0:
+		return fmt.Errorf("none of %d finished games was decided in overtime or a shootout", finished)
+	}
+	return nil
+}
+
+// Abbreviation returns the usual abbreviation of the decision, "OT" or "SO", empty for regulation time
+func (d Decision) Abbreviation() string {
+	switch d {
+	case DecisionOvertime:
+		return "OT"
+	case DecisionShootout:
+		return "SO"
+	}
+	return ""
+}
+
 // Game represents a single game/match
 type Game struct {
 	UUID      string    `json:"uuid"`
 	StartTime time.Time `json:"-"`
-	State     string    `json:"state"`
+	State     GameState `json:"state"`
+	Decision  Decision  `json:"-"` // How a finished game was decided
 	HomeTeam  Team      `json:"-"`
 	AwayTeam  Team      `json:"-"`
-	Venue     string    `json:"-"`
+	Venue     Venue     `json:"-"`
+	GameType  GameType  `json:"-"` // Set by the client, not part of the game JSON
 }
 
 // gameJSON is used for unmarshaling the nested JSON structure
 // Supports both test format (homeTeam/awayTeam) and API format (homeTeamInfo/awayTeamInfo)
 type gameJSON struct {
-	UUID             string `json:"uuid"`
-	RawStartDateTime string `json:"rawStartDateTime"`
-	State            string `json:"state"`
+	UUID             string    `json:"uuid"`
+	RawStartDateTime string    `json:"rawStartDateTime"`
+	State            GameState `json:"state"`
+	Decision         string    `json:"decision,omitempty"` // Not yet seen in a captured response, see CheckDecisions
 	// Test format
 	HomeTeam *Team `json:"homeTeam,omitempty"`
 	AwayTeam *Team `json:"awayTeam,omitempty"`
 	// API format
 	HomeTeamInfo *Team `json:"homeTeamInfo,omitempty"`
 	AwayTeamInfo *Team `json:"awayTeamInfo,omitempty"`
-	VenueInfo    struct {
-		UUID string `json:"uuid"`
-		Name string `json:"name"`
-	} `json:"venueInfo"`
+	VenueInfo    Venue `json:"venueInfo"`
+	// Not part of the API response, only present in cached games
+	GameType *GameType `json:"gameType,omitempty"`
 }
 
+// rawStartDateTimeFormat is the timestamp format used by the API
+const rawStartDateTimeFormat = "2006-01-02T15:04:05.000Z"
+
 func (g *Game) UnmarshalJSON(data []byte) error {
 	var gj gameJSON
 	if err := json.Unmarshal(data, &gj); err != nil {
@@ -186,7 +373,8 @@ func (g *Game) UnmarshalJSON(data []byte) error {
 
 	g.UUID = gj.UUID
 	g.State = gj.State
-	g.Venue = gj.VenueInfo.Name
+	g.Decision = ParseDecision(gj.Decision)
+	g.Venue = gj.VenueInfo
 
 	// Support both test format and API format
 	if gj.HomeTeamInfo != nil {
@@ -201,8 +389,12 @@ func (g *Game) UnmarshalJSON(data []byte) error {
 		g.AwayTeam = *gj.AwayTeam
 	}
 
+	if gj.GameType != nil {
+		g.GameType = *gj.GameType
+	}
+
 	// Parse the ISO 8601 timestamp
-	t, err := time.Parse("2006-01-02T15:04:05.000Z", gj.RawStartDateTime)
+	t, err := time.Parse(rawStartDateTimeFormat, gj.RawStartDateTime)
 	if err != nil {
 		return err
 	}
@@ -211,6 +403,111 @@ func (g *Game) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// MarshalJSON implements json.Marshaler for Game in the API format, so cached games round-trip
+func (g Game) MarshalJSON() ([]byte, error) {
+	gj := gameJSON{
+		UUID:             g.UUID,
+		RawStartDateTime: g.StartTime.UTC().Format(rawStartDateTimeFormat),
+		State:            g.State,
+		Decision:         string(g.Decision),
+		HomeTeamInfo:     &g.HomeTeam,
+		AwayTeamInfo:     &g.AwayTeam,
+		VenueInfo:        g.Venue,
+	}
+	if g.GameType != (GameType{}) {
+		gj.GameType = &g.GameType
+	}
+	return json.Marshal(gj)
+}
+
+// SeasonGames holds the games of one season
+type SeasonGames struct {
+	Season Season
+	Games  []Game
+}
+
+// MergeSeasonGames returns the games of several seasons as one list, without duplicates, sorted by start time
+func MergeSeasonGames(seasons []SeasonGames) []Game {
+	seen := make(map[string]bool)
+	var games []Game
+
+	for _, sg := range seasons {
+		for _, game := range sg.Games {
+			if seen[game.UUID] {
+				continue
+			}
+			seen[game.UUID] = true
+			games = append(games, game)
+		}
+	}
+
+	sort.SliceStable(games, func(i, j int) bool {
+		return games[i].StartTime.Before(games[j].StartTime)
+	})
+
+	return games
+}
+
+// GamesSince returns the games starting at or after since
+func GamesSince(games []Game, since time.Time) []Game {
+	var filtered []Game
+	for _, game := range games {
+		if !game.StartTime.Before(since) {
+			filtered = append(filtered, game)
+		}
+	}
+	return filtered
+}
+
+// SeasonSpan returns the start times of the first and last game in a list of games
+func SeasonSpan(games []Game) (first, last time.Time) {
+	for i, game := range games {
+		if i == 0 || game.StartTime.Before(first) {
+			first = game.StartTime
+		}
+		if i == 0 || game.StartTime.After(last) {
+			last = game.StartTime
+		}
+	}
+	return first, last
+}
+
+// SeasonComplete returns true if no game of a season is scheduled after now and every game
+// has been played, apart from cancelled, postponed or removed games, which are final by then
+func SeasonComplete(games []Game, now time.Time) bool {
+	played := false
+	for _, game := range games {
+		if game.StartTime.After(now) {
+			return false
+		}
+		if game.IsCancelled() {
+			continue
+		}
+		if game.State != StatePostGame {
+			return false
+		}
+		played = true
+	}
+	return played
+}
+
+// IsCancelled returns true if the game is cancelled, postponed or removed from the schedule
+func (g *Game) IsCancelled() bool {
+	return g.State == StateCancelled || g.State == StatePostponed || g.State == StateRemoved
+}
+
+// ResultNote qualifies the score of a game: "OT" or "SO" for finished games decided in
+// overtime or a shootout, "live" while the game is in progress, and empty otherwise
+func (g *Game) ResultNote() string {
+	if g.State == StateLive {
+		return "live"
+	}
+	if g.State == StatePostGame {
+		return g.Decision.Abbreviation()
+	}
+	return ""
+}
+
 // InvolvesTeam returns true if the given team (by short name) is playing in this game
 func (g *Game) InvolvesTeam(teamShortName string) bool {
 	return g.HomeTeam.ShortName == teamShortName || g.AwayTeam.ShortName == teamShortName
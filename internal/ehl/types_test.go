@@ -30,6 +30,29 @@ func TestSeasonUnmarshal(t *testing.T) {
 	}
 }
 
+func TestGameTypeUnmarshal(t *testing.T) {
+	jsonData := `{
+		"uuid": "qQ9-af37Ti40B",
+		"names": [
+			{"language": "en", "translation": "Regular season"},
+			{"language": "no", "translation": "Serie"}
+		]
+	}`
+
+	var gameType GameType
+	err := json.Unmarshal([]byte(jsonData), &gameType)
+	if err != nil {
+		t.Fatalf("failed to unmarshal game type: %v", err)
+	}
+
+	if gameType.UUID != "qQ9-af37Ti40B" {
+		t.Errorf("expected UUID 'qQ9-af37Ti40B', got '%s'", gameType.UUID)
+	}
+	if gameType.Name != "Serie" {
+		t.Errorf("expected Name 'Serie', got '%s'", gameType.Name)
+	}
+}
+
 func TestTeamUnmarshal(t *testing.T) {
 	jsonData := `{
 		"uuid": "qQ0-A0eF1CWG5",
@@ -112,8 +135,8 @@ func TestGameUnmarshal(t *testing.T) {
 	if game.AwayTeam.ShortName != "Storhamar" {
 		t.Errorf("expected AwayTeam.ShortName 'Storhamar', got '%s'", game.AwayTeam.ShortName)
 	}
-	if game.Venue != "Jordal Amfi" {
-		t.Errorf("expected Venue 'Jordal Amfi', got '%s'", game.Venue)
+	if game.Venue.Name != "Jordal Amfi" {
+		t.Errorf("expected Venue 'Jordal Amfi', got %+v", game.Venue)
 	}
 }
 
@@ -138,3 +161,215 @@ func TestGameSlug(t *testing.T) {
 		})
 	}
 }
+
+func TestVenueSlug(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"Jordal Amfi", "jordal-amfi"},
+		{"CC Amfi, Hamar", "cc-amfi-hamar"},
+		{"Nye Hamar OL-amfi", "nye-hamar-ol-amfi"},
+		{"Lørenskog Ishall", "lorenskog-ishall"},
+		{"Skien Fritidspark (Hall 1)", "skien-fritidspark-hall-1"},
+	}
+
+	for _, tt := range tests {
+		venue := Venue{Name: tt.name}
+		if got := venue.Slug(); got != tt.expected {
+			t.Errorf("Slug(%q) = %s, want %s", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestParseDecision(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected Decision
+		abbrev   string
+	}{
+		{"", DecisionRegulation, ""},
+		{"regular", DecisionRegulation, ""},
+		{"OT", DecisionOvertime, "OT"},
+		{"overtime", DecisionOvertime, "OT"},
+		{"SO", DecisionShootout, "SO"},
+		{" Shootout ", DecisionShootout, "SO"},
+	}
+
+	for _, tt := range tests {
+		got := ParseDecision(tt.value)
+		if got != tt.expected || got.Abbreviation() != tt.abbrev {
+			t.Errorf("ParseDecision(%q) = %q (%q), want %q (%q)", tt.value, got, got.Abbreviation(), tt.expected, tt.abbrev)
+		}
+	}
+}
+
+func TestCheckDecisions(t *testing.T) {
+	finished := func(n int, decision Decision, home, away int) []Game {
+		games := make([]Game, n)
+		for i := range games {
+			games[i] = Game{State: StatePostGame, Decision: decision, HomeTeam: Team{Score: home}, AwayTeam: Team{Score: away}}
+		}
+		return games
+	}
+
+	tests := []struct {
+		name    string
+		games   []Game
+		wantErr bool
+	}{
+		{"no games", nil, false},
+		{"few games, all in regulation", finished(5, DecisionRegulation, 3, 1), false},
+		{"many games, some decided", append(finished(40, DecisionRegulation, 3, 1), finished(1, DecisionShootout, 2, 1)...), false},
+		{"many games, none decided", finished(40, DecisionRegulation, 3, 1), true},
+		{"level score", append(finished(5, DecisionRegulation, 3, 1), finished(1, DecisionShootout, 0, 0)...), true},
+		{"upcoming games", []Game{{State: StatePreGame}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckDecisions(tt.games); (err != nil) != tt.wantErr {
+				t.Errorf("CheckDecisions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGameResultNote(t *testing.T) {
+	tests := []struct {
+		state    GameState
+		decision Decision
+		expected string
+		started  bool
+	}{
+		{StatePreGame, "", "", false},
+		{StateLive, "", "live", true},
+		{StatePostGame, DecisionRegulation, "", true},
+		{StatePostGame, DecisionOvertime, "OT", true},
+		{StatePostGame, DecisionShootout, "SO", true},
+		{StateCancelled, "", "", false},
+		{"intermission", "", "", false},
+	}
+
+	for _, tt := range tests {
+		game := Game{State: tt.state, Decision: tt.decision}
+		if got := game.ResultNote(); got != tt.expected {
+			t.Errorf("%s/%s: ResultNote() = %q, want %q", tt.state, tt.decision, got, tt.expected)
+		}
+		if got := tt.state.Started(); got != tt.started {
+			t.Errorf("%s: Started() = %v, want %v", tt.state, got, tt.started)
+		}
+	}
+}
+
+func TestGameDecisionRoundTrip(t *testing.T) {
+	data := []byte(`{"uuid":"g","rawStartDateTime":"2025-09-11T17:00:00.000Z","state":"post-game","decision":"SO"}`)
+
+	var game Game
+	if err := json.Unmarshal(data, &game); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if game.State != StatePostGame || game.Decision != DecisionShootout {
+		t.Fatalf("unexpected game %+v", game)
+	}
+
+	encoded, err := json.Marshal(game)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var again Game
+	if err := json.Unmarshal(encoded, &again); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if again.Decision != DecisionShootout {
+		t.Errorf("expected the decision to round-trip, got %q", again.Decision)
+	}
+}
+
+func TestParseSeries(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected Series
+		wantErr  bool
+	}{
+		{"ehl=qUu-397s1Dpwm", Series{Slug: "ehl", UUID: "qUu-397s1Dpwm", Name: "EHL"}, false},
+		{"1div=abc-123:1. divisjon", Series{Slug: "1div", UUID: "abc-123", Name: "1. divisjon"}, false},
+		{"ehl", Series{}, true},
+		{"=abc", Series{}, true},
+		{"ehl=:EHL", Series{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseSeries(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSeries() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("ParseSeries() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSeasonSlug(t *testing.T) {
+	season := Season{Name: "2024/2025"}
+	if got := season.Slug(); got != "2024-2025" {
+		t.Errorf("Slug() = %s, want 2024-2025", got)
+	}
+}
+
+func TestSeasonComplete(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	played := Game{State: "post-game", StartTime: time.Date(2025, 3, 1, 17, 0, 0, 0, time.UTC)}
+	upcoming := Game{State: "pre-game", StartTime: time.Date(2025, 9, 1, 17, 0, 0, 0, time.UTC)}
+	unplayed := Game{State: "pre-game", StartTime: time.Date(2025, 3, 2, 17, 0, 0, 0, time.UTC)}
+	cancelled := Game{State: StateCancelled, StartTime: time.Date(2025, 2, 1, 17, 0, 0, 0, time.UTC)}
+	postponed := Game{State: StatePostponed, StartTime: time.Date(2025, 2, 8, 17, 0, 0, 0, time.UTC)}
+	removed := Game{State: StateRemoved, StartTime: time.Date(2025, 2, 15, 17, 0, 0, 0, time.UTC)}
+	rescheduled := Game{State: StatePostponed, StartTime: time.Date(2025, 9, 2, 17, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		name     string
+		games    []Game
+		expected bool
+	}{
+		{"no games", nil, false},
+		{"all played", []Game{played, played}, true},
+		{"upcoming game", []Game{played, upcoming}, false},
+		{"unplayed game", []Game{played, unplayed}, false},
+		{"cancelled, postponed and removed games", []Game{cancelled, played, postponed, removed}, true},
+		{"postponed past now", []Game{played, rescheduled}, false},
+		{"only cancelled games", []Game{cancelled, removed}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SeasonComplete(tt.games, now); got != tt.expected {
+				t.Errorf("SeasonComplete() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMergeSeasonGames(t *testing.T) {
+	early := Game{UUID: "early", StartTime: time.Date(2025, 4, 1, 17, 0, 0, 0, time.UTC)}
+	late := Game{UUID: "late", StartTime: time.Date(2025, 9, 1, 17, 0, 0, 0, time.UTC)}
+
+	merged := MergeSeasonGames([]SeasonGames{
+		{Season: Season{Name: "2025/2026"}, Games: []Game{late}},
+		{Season: Season{Name: "2024/2025"}, Games: []Game{early, late}},
+	})
+
+	if len(merged) != 2 {
+		t.Fatalf("expected 2 games without duplicates, got %d", len(merged))
+	}
+	if merged[0].UUID != "early" || merged[1].UUID != "late" {
+		t.Errorf("expected games sorted by start time, got %s, %s", merged[0].UUID, merged[1].UUID)
+	}
+
+	since := GamesSince(merged, time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC))
+	if len(since) != 1 || since[0].UUID != "late" {
+		t.Errorf("GamesSince() = %v, want only late game", since)
+	}
+}
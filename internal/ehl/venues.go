@@ -0,0 +1,102 @@
+package ehl
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// venueData is the embedded venue registry, a JSON object of VenueDetails keyed by venue UUID,
+// or by the venue name reported by the API until its UUID is known. Venues reported as
+// unknown by cmd/generate are added here.
+//
+//go:embed data/venues.json
+var venueData []byte
+
+// VenueDetails is what the registry knows about a venue
+type VenueDetails struct {
+	Name       string  `json:"name"` // As reported by the API, to keep the file readable
+	Address    string  `json:"address"`
+	PostalCode string  `json:"postalCode"`
+	City       string  `json:"city"`
+	Latitude   float64 `json:"lat,omitempty"` // Coordinates are optional, both zero when unknown
+	Longitude  float64 `json:"lon,omitempty"`
+}
+
+// HasCoordinates reports whether the venue's coordinates are known
+func (d VenueDetails) HasCoordinates() bool {
+	return d.Latitude != 0 || d.Longitude != 0
+}
+
+// Locality returns the postal code and city, e.g. "0155 Oslo"
+func (d VenueDetails) Locality() string {
+	return strings.TrimSpace(d.PostalCode + " " + d.City)
+}
+
+// FullAddress returns the street address and locality, e.g. "Storgata 1, 0155 Oslo"
+func (d VenueDetails) FullAddress() string {
+	var parts []string
+	for _, part := range []string{d.Address, d.Locality()} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// VenueRegistry holds venue details keyed by venue UUID or name
+type VenueRegistry map[string]VenueDetails
+
+// DefaultVenues is the embedded venue registry
+var DefaultVenues = mustParseVenueRegistry(venueData)
+
+// ParseVenueRegistry parses a registry file, checking that every venue has a city and that
+// coordinates, if any, are valid
+func ParseVenueRegistry(data []byte) (VenueRegistry, error) {
+	var registry VenueRegistry
+	if err := json.Unmarshal(data, &registry); err != nil {
+		return nil, fmt.Errorf("failed to decode venue registry: %w", err)
+	}
+
+	for key, details := range registry {
+		if key == "" || details.City == "" {
+			return nil, fmt.Errorf("venue %q (%s): missing key or city", details.Name, key)
+		}
+		if details.Latitude < -90 || details.Latitude > 90 || details.Longitude < -180 || details.Longitude > 180 ||
+			(details.HasCoordinates() && (details.Latitude == 0 || details.Longitude == 0)) {
+			return nil, fmt.Errorf("venue %q (%s): invalid coordinates %v,%v", details.Name, key, details.Latitude, details.Longitude)
+		}
+	}
+
+	return registry, nil
+}
+
+func mustParseVenueRegistry(data []byte) VenueRegistry {
+	registry, err := ParseVenueRegistry(data)
+	if err != nil {
+		panic(err)
+	}
+	return registry
+}
+
+// Lookup returns the details of a venue by UUID, or else by name, and false if it is not in the registry
+func (r VenueRegistry) Lookup(venue Venue) (VenueDetails, bool) {
+	for _, key := range []string{venue.UUID, venue.Name} {
+		if details, ok := r[key]; key != "" && ok {
+			return details, true
+		}
+	}
+	return VenueDetails{}, false
+}
+
+// Unknown returns the venues that are not in the registry
+func (r VenueRegistry) Unknown(venues []Venue) []Venue {
+	var unknown []Venue
+	for _, venue := range venues {
+		if _, ok := r.Lookup(venue); !ok {
+			unknown = append(unknown, venue)
+		}
+	}
+	return unknown
+}
@@ -0,0 +1,92 @@
+package ehl
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseVenueRegistry(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{"empty", `{}`, false},
+		{"valid", `{"venue-1": {"name": "Testhallen", "address": "Storgata 1", "postalCode": "0155", "city": "Oslo", "lat": 59.91, "lon": 10.75}}`, false},
+		{"missing city", `{"venue-1": {"name": "Testhallen", "lat": 59.91, "lon": 10.75}}`, true},
+		{"without coordinates", `{"venue-1": {"name": "Testhallen", "city": "Oslo"}}`, false},
+		{"half the coordinates", `{"venue-1": {"name": "Testhallen", "city": "Oslo", "lat": 59.91}}`, true},
+		{"latitude out of range", `{"venue-1": {"name": "Testhallen", "city": "Oslo", "lat": 159.91, "lon": 10.75}}`, true},
+		{"not an object", `[]`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseVenueRegistry([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseVenueRegistry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDefaultVenues(t *testing.T) {
+	// The embedded registry is parsed at init; an empty file would silently disable it
+	if len(DefaultVenues) == 0 {
+		t.Fatal("expected venues in the embedded registry")
+	}
+
+	details, ok := DefaultVenues.Lookup(Venue{UUID: "not-in-registry", Name: "Jordal Amfi"})
+	if !ok || details.City != "Oslo" || !details.HasCoordinates() {
+		t.Errorf("expected Jordal Amfi with coordinates, got %+v, %v", details, ok)
+	}
+}
+
+func TestDefaultVenues_Fixtures(t *testing.T) {
+	// Every venue in the API fixtures is registered
+	for _, response := range []string{testGamesResponse, testPlayoffGamesResponse} {
+		var result gamesResponse
+		if err := json.Unmarshal([]byte(response), &result); err != nil {
+			t.Fatal(err)
+		}
+		for _, venue := range ExtractVenues(result.GameInfo) {
+			if _, ok := DefaultVenues.Lookup(venue); !ok {
+				t.Errorf("venue %q (%s) is not in the registry", venue.Name, venue.UUID)
+			}
+		}
+	}
+}
+
+func TestVenueRegistry(t *testing.T) {
+	registry := VenueRegistry{
+		"venue-1": {Name: "Testhallen", Address: "Storgata 1", PostalCode: "0155", City: "Oslo", Latitude: 59.91, Longitude: 10.75},
+		"venue-2": {Name: "Byhallen", City: "Hamar", Latitude: 60.79, Longitude: 11.07},
+	}
+
+	details, ok := registry.Lookup(Venue{UUID: "venue-1", Name: "Testhallen"})
+	if !ok || details.FullAddress() != "Storgata 1, 0155 Oslo" {
+		t.Errorf("Lookup() = %+v, %v", details, ok)
+	}
+	if details, _ := registry.Lookup(Venue{UUID: "venue-2"}); details.FullAddress() != "Hamar" {
+		t.Errorf("expected only the city without address, got %q", details.FullAddress())
+	}
+	if _, ok := registry.Lookup(Venue{Name: "Testhallen"}); ok {
+		t.Error("expected no match by the name in the entry, only by key")
+	}
+
+	// Venues whose UUID is not known yet are keyed by name
+	registry["Navnehallen"] = VenueDetails{Name: "Navnehallen", City: "Narvik"}
+	if details, ok := registry.Lookup(Venue{UUID: "venue-4", Name: "Navnehallen"}); !ok || details.City != "Narvik" {
+		t.Errorf("expected a match by name, got %+v, %v", details, ok)
+	}
+
+	unknown := registry.Unknown([]Venue{
+		{UUID: "venue-1", Name: "Testhallen"},
+		{UUID: "venue-3", Name: "Nyhallen"},
+		{UUID: "venue-4", Name: "Navnehallen"},
+		{Name: "Uten UUID"},
+	})
+	if len(unknown) != 2 || unknown[0].UUID != "venue-3" || unknown[1].Name != "Uten UUID" {
+		t.Errorf("Unknown() = %+v, want venue-3 and the venue without UUID", unknown)
+	}
+}
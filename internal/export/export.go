@@ -0,0 +1,134 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/thomasoddsund/hockeykalender/internal/ehl"
+)
+
+// Header is the first row of every export
+var Header = []string{"Dato", "Tid", "Ukedag", "Hjemmelag", "Bortelag", "Arena", "Status", "Resultat"}
+
+var weekdays = [...]string{"søndag", "mandag", "tirsdag", "onsdag", "torsdag", "fredag", "lørdag"}
+
+var stateLabels = map[ehl.GameState]string{
+	ehl.StateLive:      "Pågår",
+	ehl.StatePreGame:   "Ikke spilt",
+	ehl.StatePostGame:  "Ferdig",
+	ehl.StateCancelled: "Avlyst",
+	ehl.StatePostponed: "Utsatt",
+	ehl.StateRemoved:   "Fjernet",
+}
+
+// Row returns the columns of a game, with date and time in loc
+func Row(game ehl.Game, loc *time.Location) []string {
+	start := game.StartTime.In(loc)
+
+	state, ok := stateLabels[game.State]
+	if !ok {
+		state = string(game.State)
+	}
+
+	var result string
+	if game.State.Started() {
+		result = fmt.Sprintf("%d - %d", game.HomeTeam.Score, game.AwayTeam.Score)
+		if note := game.ResultNote(); note != "" {
+			result += " (" + note + ")"
+		}
+	}
+
+	return []string{
+		start.Format("2006-01-02"),
+		start.Format("15:04"),
+		weekdays[start.Weekday()],
+		game.HomeTeam.ShortName,
+		game.AwayTeam.ShortName,
+		game.Venue.Name,
+		state,
+		result,
+	}
+}
+
+// Rows returns the header followed by a row per game
+func Rows(games []ehl.Game, loc *time.Location) [][]string {
+	rows := [][]string{Header}
+	for _, game := range games {
+		rows = append(rows, Row(game, loc))
+	}
+	return rows
+}
+
+// WriteCSV writes games as CSV. The UTF-8 byte order mark makes Excel read Norwegian characters correctly.
+func WriteCSV(w io.Writer, games []ehl.Game, loc *time.Location) error {
+	if _, err := io.WriteString(w, "\uFEFF"); err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	cw.UseCRLF = true
+	if err := cw.WriteAll(Rows(games, loc)); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// WriteAll writes {slug}.csv and {slug}.xlsx for each team and for all games, named after seriesSlug.
+// It returns the number of files written.
+func WriteAll(dir, seriesSlug string, games []ehl.Game, teams []ehl.Team, loc *time.Location) (int, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	files := 0
+	write := func(slug, sheet string, games []ehl.Game) error {
+		if err := writeFile(filepath.Join(dir, slug+".csv"), func(w io.Writer) error {
+			return WriteCSV(w, games, loc)
+		}); err != nil {
+			return err
+		}
+		if err := writeFile(filepath.Join(dir, slug+".xlsx"), func(w io.Writer) error {
+			return WriteXLSX(w, sheet, games, loc)
+		}); err != nil {
+			return err
+		}
+		files += 2
+		return nil
+	}
+
+	for _, team := range teams {
+		var teamGames []ehl.Game
+		for _, game := range games {
+			if game.InvolvesTeam(team.ShortName) {
+				teamGames = append(teamGames, game)
+			}
+		}
+		if err := write(team.Slug(), team.ShortName, teamGames); err != nil {
+			return files, err
+		}
+	}
+
+	if err := write(seriesSlug, "Terminliste", games); err != nil {
+		return files, err
+	}
+
+	return files, nil
+}
+
+func writeFile(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Base(path), err)
+	}
+
+	if err := write(f); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,153 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thomasoddsund/hockeykalender/internal/ehl"
+)
+
+var oslo, _ = time.LoadLocation("Europe/Oslo")
+
+func testGames() ([]ehl.Game, []ehl.Team) {
+	vif := ehl.Team{UUID: "team-vif", ShortName: "Vålerenga"}
+	sth := ehl.Team{UUID: "team-sth", ShortName: "Storhamar"}
+	frisk := ehl.Team{UUID: "team-fa", ShortName: "Frisk Asker"}
+
+	played := ehl.Game{
+		UUID:      "game-1",
+		StartTime: time.Date(2025, 9, 11, 17, 0, 0, 0, time.UTC),
+		State:     ehl.StatePostGame,
+		HomeTeam:  vif,
+		AwayTeam:  sth,
+		Venue:     ehl.Venue{Name: "Jordal Amfi"},
+	}
+	played.HomeTeam.Score, played.AwayTeam.Score = 3, 2
+
+	upcoming := ehl.Game{
+		UUID:      "game-2",
+		StartTime: time.Date(2025, 11, 1, 15, 0, 0, 0, time.UTC),
+		State:     ehl.StatePreGame,
+		HomeTeam:  sth,
+		AwayTeam:  frisk,
+		Venue:     ehl.Venue{Name: "CC Amfi, Hamar"},
+	}
+
+	return []ehl.Game{played, upcoming}, []ehl.Team{frisk, sth, vif}
+}
+
+func TestRow(t *testing.T) {
+	games, _ := testGames()
+	postponed := games[1]
+	postponed.State = ehl.StatePostponed
+	unknown := games[1]
+	unknown.State = "delayed"
+	live := games[1]
+	live.State = ehl.StateLive
+	live.HomeTeam.Score = 1
+	shootout := games[0]
+	shootout.Decision = ehl.DecisionShootout
+
+	tests := []struct {
+		name     string
+		game     ehl.Game
+		expected []string
+	}{
+		{"played, summer time", games[0], []string{"2025-09-11", "19:00", "torsdag", "Vålerenga", "Storhamar", "Jordal Amfi", "Ferdig", "3 - 2"}},
+		{"upcoming, winter time", games[1], []string{"2025-11-01", "16:00", "lørdag", "Storhamar", "Frisk Asker", "CC Amfi, Hamar", "Ikke spilt", ""}},
+		{"postponed", postponed, []string{"2025-11-01", "16:00", "lørdag", "Storhamar", "Frisk Asker", "CC Amfi, Hamar", "Utsatt", ""}},
+		{"unknown state", unknown, []string{"2025-11-01", "16:00", "lørdag", "Storhamar", "Frisk Asker", "CC Amfi, Hamar", "delayed", ""}},
+		{"live", live, []string{"2025-11-01", "16:00", "lørdag", "Storhamar", "Frisk Asker", "CC Amfi, Hamar", "Pågår", "1 - 0 (live)"}},
+		{"shootout", shootout, []string{"2025-09-11", "19:00", "torsdag", "Vålerenga", "Storhamar", "Jordal Amfi", "Ferdig", "3 - 2 (SO)"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Row(tt.game, oslo)
+			if strings.Join(got, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	games, _ := testGames()
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, games, oslo); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+
+	data, ok := strings.CutPrefix(buf.String(), "\uFEFF")
+	if !ok {
+		t.Error("expected UTF-8 byte order mark")
+	}
+
+	records, err := csv.NewReader(strings.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected header and 2 rows, got %d", len(records))
+	}
+	if strings.Join(records[0], ",") != strings.Join(Header, ",") {
+		t.Errorf("unexpected header %v", records[0])
+	}
+	if records[2][5] != "CC Amfi, Hamar" {
+		t.Errorf("expected venue with comma to survive quoting, got %q", records[2][5])
+	}
+	if !strings.Contains(data, "\r\n") {
+		t.Error("expected CRLF line endings")
+	}
+}
+
+func TestWriteAll(t *testing.T) {
+	dir := t.TempDir()
+	games, teams := testGames()
+
+	files, err := WriteAll(dir, "ehl", games, teams, oslo)
+	if err != nil {
+		t.Fatalf("WriteAll failed: %v", err)
+	}
+	// 3 teams + league, CSV and XLSX
+	if files != 8 {
+		t.Errorf("expected 8 files, got %d", files)
+	}
+
+	tests := []struct {
+		file string
+		rows int
+	}{
+		{"valerenga.csv", 2},
+		{"storhamar.csv", 3},
+		{"frisk-asker.csv", 2},
+		{"ehl.csv", 3},
+	}
+
+	for _, tt := range tests {
+		data, err := os.ReadFile(filepath.Join(dir, tt.file))
+		if err != nil {
+			t.Errorf("expected %s to exist", tt.file)
+			continue
+		}
+		records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+		if err != nil {
+			t.Errorf("%s: invalid CSV: %v", tt.file, err)
+			continue
+		}
+		if len(records) != tt.rows {
+			t.Errorf("%s: expected %d rows, got %d", tt.file, tt.rows, len(records))
+		}
+
+		xlsx := strings.TrimSuffix(tt.file, ".csv") + ".xlsx"
+		if _, err := os.Stat(filepath.Join(dir, xlsx)); err != nil {
+			t.Errorf("expected %s to exist", xlsx)
+		}
+	}
+}
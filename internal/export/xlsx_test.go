@@ -0,0 +1,120 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+)
+
+func TestWriteXLSX(t *testing.T) {
+	games, _ := testGames()
+
+	var buf bytes.Buffer
+	if err := WriteXLSX(&buf, "Vålerenga", games, oslo); err != nil {
+		t.Fatalf("WriteXLSX failed: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("invalid zip: %v", err)
+	}
+
+	parts := make(map[string][]byte)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", f.Name, err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		parts[f.Name] = data
+
+		var v any
+		if err := xml.Unmarshal(data, &v); err != nil {
+			t.Errorf("%s is not well-formed XML: %v", f.Name, err)
+		}
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("missing part %s", name)
+		}
+	}
+
+	var sheet struct {
+		Rows []struct {
+			Cells []struct {
+				Ref  string `xml:"r,attr"`
+				Text string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := xml.Unmarshal(parts["xl/worksheets/sheet1.xml"], &sheet); err != nil {
+		t.Fatalf("failed to parse sheet: %v", err)
+	}
+
+	if len(sheet.Rows) != 3 {
+		t.Fatalf("expected header and 2 rows, got %d", len(sheet.Rows))
+	}
+	if cell := sheet.Rows[0].Cells[0]; cell.Ref != "A1" || cell.Text != "Dato" {
+		t.Errorf("unexpected first cell %+v", cell)
+	}
+	if cell := sheet.Rows[1].Cells[7]; cell.Ref != "H2" || cell.Text != "3 - 2" {
+		t.Errorf("unexpected result cell %+v", cell)
+	}
+	if cell := sheet.Rows[2].Cells[3]; cell.Text != "Storhamar" {
+		t.Errorf("unexpected home team cell %+v", cell)
+	}
+
+	if !bytes.Contains(parts["xl/workbook.xml"], []byte(`name="Vålerenga"`)) {
+		t.Errorf("expected sheet name in workbook:\n%s", parts["xl/workbook.xml"])
+	}
+
+	// Identical input gives identical files
+	var again bytes.Buffer
+	WriteXLSX(&again, "Vålerenga", games, oslo)
+	if !bytes.Equal(buf.Bytes(), again.Bytes()) {
+		t.Error("expected reproducible output")
+	}
+}
+
+func TestColumn(t *testing.T) {
+	tests := []struct {
+		index    int
+		expected string
+	}{
+		{0, "A"},
+		{7, "H"},
+		{25, "Z"},
+		{26, "AA"},
+		{27, "AB"},
+		{701, "ZZ"},
+		{702, "AAA"},
+	}
+
+	for _, tt := range tests {
+		if got := column(tt.index); got != tt.expected {
+			t.Errorf("column(%d) = %s, want %s", tt.index, got, tt.expected)
+		}
+	}
+}
+
+func TestSheetName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"Vålerenga", "Vålerenga"},
+		{"A/B: [test]?", "AB test"},
+		{"???", "Terminliste"},
+		{"Et veldig langt lagnavn som er for langt", "Et veldig langt lagnavn som er "},
+	}
+
+	for _, tt := range tests {
+		if got := sheetName(tt.name); got != tt.expected {
+			t.Errorf("sheetName(%q) = %q, want %q", tt.name, got, tt.expected)
+		}
+	}
+}
@@ -2,84 +2,116 @@ package ical
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 )
 
-// Alarm represents a reminder before an event
-type Alarm int
+// Alarm represents a reminder a fixed duration before an event
+type Alarm time.Duration
 
+// Common alarm offsets
 const (
-	Alarm1Day Alarm = iota
-	Alarm3Hours
-	Alarm1Hour
-	Alarm15Min
+	Alarm1Day   = Alarm(24 * time.Hour)
+	Alarm3Hours = Alarm(3 * time.Hour)
+	Alarm1Hour  = Alarm(1 * time.Hour)
+	Alarm15Min  = Alarm(15 * time.Minute)
 )
 
-// AllAlarms contains all available alarm types in order
-var AllAlarms = []Alarm{Alarm1Day, Alarm3Hours, Alarm1Hour, Alarm15Min}
+// DefaultAlarms are the alarms combined into the default presets, in filename order
+var DefaultAlarms = []Alarm{Alarm1Day, Alarm3Hours, Alarm1Hour, Alarm15Min}
+
+// Limits on parsed alarms, so that suffixes from file names and URLs stay reasonable
+const (
+	MaxAlarm    = Alarm(14 * 24 * time.Hour) // Longest offset, 14d, 336h or 20160m
+	MaxAlarmSet = 4                          // Most alarms in one set
+)
+
+// alarmPattern matches alarm suffixes like "2d", "3h" or "30m"
+var alarmPattern = regexp.MustCompile(`^([1-9][0-9]*)([dhm])$`)
+
+// ParseAlarm parses an alarm from its filename suffix, e.g. "2h", "30m" or "2d".
+// Alarms longer than MaxAlarm are rejected.
+func ParseAlarm(suffix string) (Alarm, error) {
+	m := alarmPattern.FindStringSubmatch(suffix)
+	if m == nil {
+		return 0, fmt.Errorf("invalid alarm %q: expected a number followed by d, h or m", suffix)
+	}
+
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid alarm %q: %w", suffix, err)
+	}
+
+	unit := time.Minute
+	switch m[2] {
+	case "d":
+		unit = 24 * time.Hour
+	case "h":
+		unit = time.Hour
+	}
+
+	// Compare the count first, as a large count overflows the duration
+	if n > int(MaxAlarm.Duration()/unit) {
+		return 0, fmt.Errorf("invalid alarm %q: longer than %s", suffix, MaxAlarm.Suffix())
+	}
+	return Alarm(time.Duration(n) * unit), nil
+}
+
+// split returns the alarm as a count of its largest whole unit: "d", "h" or "m"
+func (a Alarm) split() (int, string) {
+	d := time.Duration(a)
+	switch {
+	case d%(24*time.Hour) == 0:
+		return int(d / (24 * time.Hour)), "d"
+	case d%time.Hour == 0:
+		return int(d / time.Hour), "h"
+	default:
+		return int(d / time.Minute), "m"
+	}
+}
 
 // Trigger returns the iCal TRIGGER value for this alarm
 func (a Alarm) Trigger() string {
-	switch a {
-	case Alarm1Day:
-		return "-P1D"
-	case Alarm3Hours:
-		return "-PT3H"
-	case Alarm1Hour:
-		return "-PT1H"
-	case Alarm15Min:
-		return "-PT15M"
+	n, unit := a.split()
+	switch unit {
+	case "d":
+		return fmt.Sprintf("-P%dD", n)
+	case "h":
+		return fmt.Sprintf("-PT%dH", n)
 	default:
-		return ""
+		return fmt.Sprintf("-PT%dM", n)
 	}
 }
 
-// Suffix returns the filename suffix for this alarm
+// Suffix returns the filename suffix for this alarm, the inverse of ParseAlarm
 func (a Alarm) Suffix() string {
-	switch a {
-	case Alarm1Day:
-		return "1d"
-	case Alarm3Hours:
-		return "3h"
-	case Alarm1Hour:
-		return "1h"
-	case Alarm15Min:
-		return "15m"
-	default:
-		return ""
-	}
+	n, unit := a.split()
+	return strconv.Itoa(n) + unit
 }
 
 // Duration returns the time.Duration for this alarm
 func (a Alarm) Duration() time.Duration {
-	switch a {
-	case Alarm1Day:
-		return 24 * time.Hour
-	case Alarm3Hours:
-		return 3 * time.Hour
-	case Alarm1Hour:
-		return 1 * time.Hour
-	case Alarm15Min:
-		return 15 * time.Minute
-	default:
-		return 0
-	}
+	return time.Duration(a)
 }
 
 // Description returns a Norwegian description for the alarm
 func (a Alarm) Description(matchSummary string) string {
-	switch a {
-	case Alarm1Day:
+	n, unit := a.split()
+	switch {
+	case unit == "d" && n == 1:
 		return matchSummary + " i morgen"
-	case Alarm3Hours:
-		return matchSummary + " om 3 timer"
-	case Alarm1Hour:
+	case unit == "d":
+		return fmt.Sprintf("%s om %d dager", matchSummary, n)
+	case unit == "h" && n == 1:
 		return matchSummary + " om 1 time"
-	case Alarm15Min:
-		return matchSummary + " om 15 minutter"
+	case unit == "h":
+		return fmt.Sprintf("%s om %d timer", matchSummary, n)
+	case n == 1:
+		return matchSummary + " om 1 minutt"
 	default:
-		return matchSummary
+		return fmt.Sprintf("%s om %d minutter", matchSummary, n)
 	}
 }
 
@@ -96,27 +128,85 @@ func AlarmSetSuffix(alarms []Alarm) string {
 	return strings.Join(suffixes, "-")
 }
 
-// FormatVALARM generates the iCal VALARM component
+// ParseAlarmSet parses a combined suffix like "1d-1h", the inverse of AlarmSetSuffix.
+// Sets of more than MaxAlarmSet alarms are rejected.
+func ParseAlarmSet(suffix string) ([]Alarm, error) {
+	if suffix == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(suffix, "-")
+	if len(parts) > MaxAlarmSet {
+		return nil, fmt.Errorf("invalid alarm set %q: more than %d alarms", suffix, MaxAlarmSet)
+	}
+
+	var alarms []Alarm
+	for _, part := range parts {
+		alarm, err := ParseAlarm(part)
+		if err != nil {
+			return nil, err
+		}
+		alarms = append(alarms, alarm)
+	}
+	return alarms, nil
+}
+
+// ParseAlarmPresets parses a comma-separated list of alarm sets, e.g. "none,1h,1d-1h,30m".
+// "none" is the preset without alarms.
+func ParseAlarmPresets(spec string) ([][]Alarm, error) {
+	var presets [][]Alarm
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "none" {
+			presets = append(presets, nil)
+			continue
+		}
+
+		alarms, err := ParseAlarmSet(part)
+		if err != nil {
+			return nil, err
+		}
+		if len(alarms) == 0 {
+			return nil, fmt.Errorf("empty alarm preset in %q, use \"none\"", spec)
+		}
+		presets = append(presets, alarms)
+	}
+	return presets, nil
+}
+
+// FormatVALARM generates the iCal VALARM component as CRLF-terminated content lines
 func FormatVALARM(alarm Alarm, matchSummary string) string {
-	return fmt.Sprintf("BEGIN:VALARM\r\nTRIGGER:%s\r\nACTION:DISPLAY\r\nDESCRIPTION:%s\r\nEND:VALARM",
-		alarm.Trigger(),
-		alarm.Description(matchSummary))
+	var w contentWriter
+	writeVALARM(&w, alarm, matchSummary)
+	return w.String()
 }
 
-// GenerateAlarmCombinations returns all 16 possible combinations of alarms
-func GenerateAlarmCombinations() [][]Alarm {
-	combinations := make([][]Alarm, 0, 16)
-
-	// Generate all 2^4 = 16 combinations using bit manipulation
-	for i := 0; i < 16; i++ {
-		var combo []Alarm
-		for j, alarm := range AllAlarms {
-			if i&(1<<j) != 0 {
-				combo = append(combo, alarm)
-			}
+func writeVALARM(w *contentWriter, alarm Alarm, matchSummary string) {
+	w.Begin("VALARM")
+	w.Raw("TRIGGER", alarm.Trigger())
+	w.Raw("ACTION", "DISPLAY")
+	w.Text("DESCRIPTION", alarm.Description(matchSummary))
+	w.End("VALARM")
+}
+
+// AlarmCombinations returns every subset of alarms, keeping their order within each subset.
+// The first combination is empty and the last contains all alarms.
+func AlarmCombinations(alarms []Alarm) [][]Alarm {
+	combinations := [][]Alarm{nil}
+
+	for _, alarm := range alarms {
+		n := len(combinations)
+		for _, combo := range combinations[:n] {
+			extended := append(append([]Alarm(nil), combo...), alarm)
+			combinations = append(combinations, extended)
 		}
-		combinations = append(combinations, combo)
 	}
 
 	return combinations
 }
+
+// DefaultAlarmPresets returns the alarm sets generated when no presets are configured:
+// all 16 combinations of the default alarms, which the web page links to
+func DefaultAlarmPresets() [][]Alarm {
+	return AlarmCombinations(DefaultAlarms)
+}
@@ -48,8 +48,8 @@ func TestAlarmSuffix(t *testing.T) {
 	}
 }
 
-func TestGenerateAlarmCombinations(t *testing.T) {
-	combos := GenerateAlarmCombinations()
+func TestDefaultAlarmPresets(t *testing.T) {
+	combos := DefaultAlarmPresets()
 
 	// Should be 16 combinations (2^4)
 	if len(combos) != 16 {
@@ -154,3 +154,122 @@ func TestAlarmDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestParseAlarm(t *testing.T) {
+	tests := []struct {
+		suffix   string
+		expected time.Duration
+		trigger  string
+		wantErr  bool
+	}{
+		{"2h", 2 * time.Hour, "-PT2H", false},
+		{"30m", 30 * time.Minute, "-PT30M", false},
+		{"2d", 48 * time.Hour, "-P2D", false},
+		{"90m", 90 * time.Minute, "-PT90M", false},
+		{"36h", 36 * time.Hour, "-PT36H", false},
+		{"14d", 14 * 24 * time.Hour, "-P14D", false},
+		{"15d", 0, "", true},
+		{"337h", 0, "", true},
+		{"20161m", 0, "", true},
+		{"106751d", 0, "", true}, // Overflows time.Duration
+		{"99999999999999999999d", 0, "", true},
+		{"0h", 0, "", true},
+		{"01h", 0, "", true},
+		{"2w", 0, "", true},
+		{"h", 0, "", true},
+		{"asker", 0, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.suffix, func(t *testing.T) {
+			alarm, err := ParseAlarm(tt.suffix)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAlarm() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if alarm.Duration() != tt.expected {
+				t.Errorf("Duration() = %v, want %v", alarm.Duration(), tt.expected)
+			}
+			if alarm.Trigger() != tt.trigger {
+				t.Errorf("Trigger() = %s, want %s", alarm.Trigger(), tt.trigger)
+			}
+			if alarm.Suffix() != tt.suffix {
+				t.Errorf("Suffix() = %s, want %s (round trip)", alarm.Suffix(), tt.suffix)
+			}
+		})
+	}
+}
+
+func TestParseAlarmSet(t *testing.T) {
+	tests := []struct {
+		suffix  string
+		count   int
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"1d-1h", 2, false},
+		{"1d-3h-1h-15m", 4, false},
+		{"2d-1d-3h-1h-15m", 0, true},
+		{"1h-15d", 0, true},
+		{"1h-", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.suffix, func(t *testing.T) {
+			alarms, err := ParseAlarmSet(tt.suffix)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAlarmSet() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(alarms) != tt.count {
+				t.Errorf("ParseAlarmSet() = %v, want %d alarms", alarms, tt.count)
+			}
+		})
+	}
+}
+
+func TestAlarmDescription_Custom(t *testing.T) {
+	tests := []struct {
+		suffix   string
+		expected string
+	}{
+		{"2d", "Vålerenga vs Storhamar om 2 dager"},
+		{"2h", "Vålerenga vs Storhamar om 2 timer"},
+		{"30m", "Vålerenga vs Storhamar om 30 minutter"},
+		{"1m", "Vålerenga vs Storhamar om 1 minutt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.suffix, func(t *testing.T) {
+			alarm, err := ParseAlarm(tt.suffix)
+			if err != nil {
+				t.Fatalf("ParseAlarm failed: %v", err)
+			}
+			if got := alarm.Description("Vålerenga vs Storhamar"); got != tt.expected {
+				t.Errorf("Description() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseAlarmPresets(t *testing.T) {
+	presets, err := ParseAlarmPresets("none, 1h, 1d-30m")
+	if err != nil {
+		t.Fatalf("ParseAlarmPresets failed: %v", err)
+	}
+
+	got := make([]string, len(presets))
+	for i, p := range presets {
+		got[i] = AlarmSetSuffix(p)
+	}
+	if strings.Join(got, ",") != ",1h,1d-30m" {
+		t.Errorf("ParseAlarmPresets() = %v", got)
+	}
+
+	for _, spec := range []string{"1h,", "1h,2x", ""} {
+		if _, err := ParseAlarmPresets(spec); err == nil {
+			t.Errorf("ParseAlarmPresets(%q): expected error", spec)
+		}
+	}
+}
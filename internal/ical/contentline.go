@@ -0,0 +1,91 @@
+package ical
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// maxLineOctets is the maximum length of a content line before folding (RFC 5545 section 3.1)
+const maxLineOctets = 75
+
+// textEscaper escapes TEXT property values (RFC 5545 section 3.3.11)
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+// EscapeText escapes backslashes, semicolons, commas and newlines in a TEXT value
+func EscapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// QuoteParam quotes a property parameter value (RFC 5545 section 3.2), dropping the
+// double quotes and control characters a quoted value cannot contain
+func QuoteParam(s string) string {
+	return `"` + strings.Map(func(r rune) rune {
+		if r == '"' || r < ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, s) + `"`
+}
+
+// FoldLine splits a content line into lines of at most 75 octets, joined by CRLF
+// followed by a space. Multi-byte UTF-8 characters are never split.
+func FoldLine(line string) string {
+	if len(line) <= maxLineOctets {
+		return line
+	}
+
+	var sb strings.Builder
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		sb.WriteString(line[:cut])
+		sb.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts towards the limit
+		limit = maxLineOctets - 1
+	}
+	sb.WriteString(line)
+
+	return sb.String()
+}
+
+// contentWriter writes folded, CRLF-terminated iCal content lines
+type contentWriter struct {
+	sb strings.Builder
+}
+
+// Raw writes a property whose value is already formatted, e.g. a date or duration.
+// name may include parameters, e.g. "DTSTART;TZID=Europe/Oslo".
+func (w *contentWriter) Raw(name, value string) {
+	w.sb.WriteString(FoldLine(name + ":" + value))
+	w.sb.WriteString("\r\n")
+}
+
+// Text writes a property with an escaped TEXT value
+func (w *contentWriter) Text(name, value string) {
+	w.Raw(name, EscapeText(value))
+}
+
+// Begin starts a component, e.g. "VEVENT"
+func (w *contentWriter) Begin(component string) {
+	w.Raw("BEGIN", component)
+}
+
+// End ends a component
+func (w *contentWriter) End(component string) {
+	w.Raw("END", component)
+}
+
+func (w *contentWriter) String() string {
+	return w.sb.String()
+}
@@ -0,0 +1,121 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"plain", "Jordal Amfi", "Jordal Amfi"},
+		{"comma", "CC Amfi, Hamar", `CC Amfi\, Hamar`},
+		{"semicolon", "Hall A; Bane 2", `Hall A\; Bane 2`},
+		{"backslash", `C:\arena`, `C:\\arena`},
+		{"newline", "Jordal Amfi\nOslo", `Jordal Amfi\nOslo`},
+		{"crlf", "Jordal Amfi\r\nOslo", `Jordal Amfi\nOslo`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EscapeText(tt.input); got != tt.expected {
+				t.Errorf("EscapeText() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFoldLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{"short", "SUMMARY:Vålerenga vs Storhamar"},
+		{"exactly 75 octets", "SUMMARY:" + strings.Repeat("a", 67)},
+		{"ascii", "DESCRIPTION:" + strings.Repeat("abcdefghij", 20)},
+		{"multi-byte", "SUMMARY:" + strings.Repeat("Vålerenga Lørenskog Ærø ", 10)},
+		{"multi-byte at boundary", "SUMMARY:" + strings.Repeat("a", 66) + strings.Repeat("ø", 10)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			folded := FoldLine(tt.line)
+
+			for i, part := range strings.Split(folded, "\r\n") {
+				if len(part) > maxLineOctets {
+					t.Errorf("line %d is %d octets, want at most %d", i, len(part), maxLineOctets)
+				}
+				if !utf8.ValidString(part) {
+					t.Errorf("line %d splits a UTF-8 character: %q", i, part)
+				}
+				if i > 0 && !strings.HasPrefix(part, " ") {
+					t.Errorf("continuation line %d does not start with a space", i)
+				}
+			}
+
+			// Unfolding restores the original line
+			if unfolded := strings.ReplaceAll(folded, "\r\n ", ""); unfolded != tt.line {
+				t.Errorf("unfolded line mismatch:\n got  %q\n want %q", unfolded, tt.line)
+			}
+		})
+	}
+}
+
+func TestGenerateCalendar_EscapesAndFolds(t *testing.T) {
+	games := makeTestGames()[:1]
+	games[0].Venue.Name = "Jordal Amfi, Oslo; inngang\nB"
+	games[0].HomeTeam.ShortName = strings.Repeat("Vålerenga ", 8)
+
+	result := GenerateCalendar(singleSeason(games), Filter{}, []Alarm{Alarm1Hour}, "EHL")
+
+	if !strings.Contains(result, `LOCATION:Jordal Amfi\, Oslo\; inngang\nB`) {
+		t.Error("expected escaped LOCATION")
+	}
+
+	for i, line := range strings.Split(strings.TrimSuffix(result, "\r\n"), "\r\n") {
+		if len(line) > maxLineOctets {
+			t.Errorf("line %d is %d octets: %q", i, len(line), line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("line %d is not valid UTF-8: %q", i, line)
+		}
+	}
+
+	// The long alarm description is folded as well
+	unfolded := strings.ReplaceAll(result, "\r\n ", "")
+	if !strings.Contains(unfolded, "DESCRIPTION:"+strings.Repeat("Vålerenga ", 8)+" vs Storhamar om 1 time") {
+		t.Error("expected complete alarm DESCRIPTION after unfolding")
+	}
+}
+
+func TestFormatVALARM_Escapes(t *testing.T) {
+	result := FormatVALARM(Alarm1Hour, "Vålerenga, Oslo vs Storhamar")
+
+	if !strings.Contains(result, `DESCRIPTION:Vålerenga\, Oslo vs Storhamar om 1 time`) {
+		t.Errorf("expected escaped DESCRIPTION, got %q", result)
+	}
+	if !strings.HasSuffix(result, "END:VALARM\r\n") {
+		t.Error("expected VALARM to end with a CRLF-terminated END line")
+	}
+}
+
+func TestQuoteParam(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"Storgata 1, 0155 Oslo", `"Storgata 1, 0155 Oslo"`},
+		{`Hallen "Amfi"; Hamar:1`, `"Hallen Amfi; Hamar:1"`},
+		{"Line\nbreak", `"Linebreak"`},
+	}
+
+	for _, tt := range tests {
+		if got := QuoteParam(tt.value); got != tt.expected {
+			t.Errorf("QuoteParam(%q) = %s, want %s", tt.value, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package ical
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/thomasoddsund/hockeykalender/internal/ehl"
+	"github.com/thomasoddsund/hockeykalender/internal/standings"
+)
+
+// formGames is the number of recent results shown per team
+const formGames = 5
+
+// seasonContext holds what is known about the games passed to GenerateCalendar,
+// for the DESCRIPTION of upcoming games
+type seasonContext struct {
+	games   []ehl.Game                 // All games, ordered by start time
+	seasons map[string]ehl.Season      // Season of each game, by game UUID
+	tables  map[string]standings.Table // Regular season table, by season UUID
+}
+
+func newSeasonContext(seasons []ehl.SeasonGames) *seasonContext {
+	c := &seasonContext{
+		games:   ehl.MergeSeasonGames(seasons),
+		seasons: make(map[string]ehl.Season),
+		tables:  make(map[string]standings.Table),
+	}
+	for _, sg := range seasons {
+		for _, game := range sg.Games {
+			c.seasons[game.UUID] = sg.Season
+		}
+		c.tables[sg.Season.UUID] = standings.Compute(standings.RegularSeason(sg.Games))
+	}
+	return c
+}
+
+// describe returns the DESCRIPTION of a game that has not started: both teams' table
+// position and recent results, and this season's games between them. It is empty when
+// nothing has been played yet.
+func (c *seasonContext) describe(game ehl.Game) string {
+	home, away := game.HomeTeam.ShortName, game.AwayTeam.ShortName
+	season := c.seasons[game.UUID]
+	var lines []string
+
+	table := c.tables[season.UUID]
+	homeRow, homeOK := table.Find(home)
+	awayRow, awayOK := table.Find(away)
+	if homeOK && awayOK && (homeRow.Played > 0 || awayRow.Played > 0) {
+		lines = append(lines, "Tabell: "+position(homeRow)+", "+position(awayRow))
+	}
+
+	for _, team := range []string{home, away} {
+		if form := c.form(team, game); form != "" {
+			lines = append(lines, fmt.Sprintf("Form %s: %s", team, form))
+		}
+	}
+
+	var meetings []string
+	for _, other := range c.games {
+		if c.seasons[other.UUID].UUID != season.UUID || !standings.Counts(other) {
+			continue
+		}
+		if other.InvolvesTeam(home) && other.InvolvesTeam(away) {
+			meetings = append(meetings, result(other))
+		}
+	}
+	if len(meetings) > 0 {
+		lines = append(lines, fmt.Sprintf("Innbyrdes %s: %s", season.Name, strings.Join(meetings, ", ")))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// form returns a team's last results before game, oldest first, e.g. "S T SOT S TOT"
+func (c *seasonContext) form(team string, game ehl.Game) string {
+	var outcomes []string
+	for _, other := range c.games {
+		if !other.StartTime.Before(game.StartTime) || !standings.Counts(other) || !other.InvolvesTeam(team) {
+			continue
+		}
+		goalsFor, goalsAgainst := other.HomeTeam.Score, other.AwayTeam.Score
+		if other.AwayTeam.ShortName == team {
+			goalsFor, goalsAgainst = goalsAgainst, goalsFor
+		}
+		outcomes = append(outcomes, outcomeLabels[standings.Points(goalsFor, goalsAgainst, other.Decision)])
+	}
+
+	return strings.Join(outcomes[max(0, len(outcomes)-formGames):], " ")
+}
+
+// outcomeLabels abbreviates a result by the points it gave, as in the standings page
+var outcomeLabels = map[int]string{
+	standings.PointsRegulationWin:  "S",
+	standings.PointsOvertimeWin:    "SOT",
+	standings.PointsOvertimeLoss:   "TOT",
+	standings.PointsRegulationLoss: "T",
+}
+
+// position describes a team's table position, e.g. "Vålerenga 2. plass (24 poeng)"
+func position(row standings.Row) string {
+	return fmt.Sprintf("%s %d. plass (%d poeng)", row.Team.ShortName, row.Position, row.Points)
+}
+
+// result formats the score of a started game, e.g. "Vålerenga 3 - 2 Storhamar (OT)"
+func result(game ehl.Game) string {
+	s := fmt.Sprintf("%s %d - %d %s", game.HomeTeam.ShortName, game.HomeTeam.Score, game.AwayTeam.Score, game.AwayTeam.ShortName)
+	if note := game.ResultNote(); note != "" {
+		s += " (" + note + ")"
+	}
+	return s
+}
@@ -0,0 +1,121 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thomasoddsund/hockeykalender/internal/ehl"
+)
+
+func contextGame(uuid string, day int, home, away string, homeScore, awayScore int, decision ehl.Decision) ehl.Game {
+	return ehl.Game{
+		UUID:      uuid,
+		StartTime: time.Date(2025, 9, day, 17, 0, 0, 0, time.UTC),
+		State:     ehl.StatePostGame,
+		Decision:  decision,
+		HomeTeam:  ehl.Team{UUID: home, ShortName: home, Score: homeScore},
+		AwayTeam:  ehl.Team{UUID: away, ShortName: away, Score: awayScore},
+	}
+}
+
+func contextSeason() []ehl.Game {
+	next := contextGame("next", 20, "Vålerenga", "Storhamar", 0, 0, "")
+	next.State = ehl.StatePreGame
+
+	return []ehl.Game{
+		contextGame("g1", 1, "Vålerenga", "Storhamar", 3, 2, ehl.DecisionOvertime),
+		contextGame("g2", 3, "Narvik", "Vålerenga", 4, 1, ""),
+		contextGame("g3", 5, "Storhamar", "Narvik", 5, 0, ""),
+		contextGame("g4", 7, "Vålerenga", "Narvik", 2, 1, ""),
+		contextGame("g5", 9, "Storhamar", "Vålerenga", 2, 1, ehl.DecisionShootout),
+		contextGame("g6", 11, "Vålerenga", "Lillehammer", 6, 1, ""),
+		contextGame("g7", 13, "Lillehammer", "Vålerenga", 0, 1, ""),
+		next,
+	}
+}
+
+func TestSeasonContext_Describe(t *testing.T) {
+	seasons := singleSeason(contextSeason())
+	c := newSeasonContext(seasons)
+	games := seasons[0].Games
+
+	got := c.describe(games[len(games)-1])
+	expected := strings.Join([]string{
+		"Tabell: Vålerenga 1. plass (12 poeng), Storhamar 2. plass (6 poeng)",
+		"Form Vålerenga: T S TOT S S",
+		"Form Storhamar: TOT S SOT",
+		"Innbyrdes 2025/2026: Vålerenga 3 - 2 Storhamar (OT), Storhamar 2 - 1 Vålerenga (SO)",
+	}, "\n")
+	if got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+
+	// Form only covers games before the described one
+	if got := c.form("Vålerenga", games[2]); got != "SOT T" {
+		t.Errorf("expected form before 5 September to be %q, got %q", "SOT T", got)
+	}
+}
+
+func TestSeasonContext_DescribeBeforeSeason(t *testing.T) {
+	game := makeTestGames()[0]
+	c := newSeasonContext(singleSeason([]ehl.Game{game}))
+
+	if got := c.describe(game); got != "" {
+		t.Errorf("expected no description before any game is played, got %q", got)
+	}
+}
+
+func TestGenerateCalendar_WithSeasonContext(t *testing.T) {
+	games := contextSeason()
+	// Names that need escaping
+	for i := range games {
+		for _, team := range []*ehl.Team{&games[i].HomeTeam, &games[i].AwayTeam} {
+			if team.ShortName == "Storhamar" {
+				team.ShortName = "Hamar; Storhamar, IL"
+			}
+		}
+	}
+
+	result := GenerateCalendar(singleSeason(games), TeamFilter("Vålerenga"), nil, "EHL", WithSeasonContext())
+	unfolded := strings.ReplaceAll(result, "\r\n ", "")
+
+	expected := `DESCRIPTION:Tabell: Vålerenga 1. plass (12 poeng)\, Hamar\; Storhamar\, IL 2. plass (6 poeng)\nForm Vålerenga: T S TOT S S\nForm Hamar\; Storhamar\, IL: TOT S SOT\nInnbyrdes 2025/2026: Vålerenga 3 - 2 Hamar\; Storhamar\, IL (OT)\, Hamar\; Storhamar\, IL 2 - 1 Vålerenga (SO)` + "\r\n"
+	if !strings.Contains(unfolded, expected) {
+		t.Errorf("expected escaped description %q in:\n%s", expected, unfolded)
+	}
+
+	// Only the upcoming game gets a description
+	if count := strings.Count(unfolded, "DESCRIPTION:"); count != 1 {
+		t.Errorf("expected 1 DESCRIPTION, got %d", count)
+	}
+
+	// Without the option there is no description
+	if plain := GenerateCalendar(singleSeason(games), TeamFilter("Vålerenga"), nil, "EHL"); strings.Contains(plain, "DESCRIPTION:") {
+		t.Error("expected no DESCRIPTION without WithSeasonContext")
+	}
+}
+
+func TestDescriptions(t *testing.T) {
+	games := contextSeason()
+	cancelled := contextGame("cancelled", 25, "Narvik", "Storhamar", 0, 0, "")
+	cancelled.State = ehl.StateCancelled
+	seasons := singleSeason(append(games, cancelled))
+
+	descriptions := Descriptions(seasons, WithSeasonContext())
+	if got := descriptions["next"]; !strings.HasPrefix(got, "Tabell: Vålerenga 1. plass (12 poeng)") {
+		t.Errorf("expected the season context of the upcoming game, got %q", got)
+	}
+	if got, ok := descriptions["g1"]; !ok || got != "" {
+		t.Errorf("expected an empty description for a finished game, got %q, %v", got, ok)
+	}
+	if _, ok := descriptions["cancelled"]; ok {
+		t.Error("expected no description for a cancelled game")
+	}
+
+	// The same text as in the calendar
+	calendar := strings.ReplaceAll(GenerateCalendar(seasons, Filter{}, nil, "EHL", WithSeasonContext()), "\r\n ", "")
+	if !strings.Contains(calendar, "DESCRIPTION:Tabell: Vålerenga 1. plass (12 poeng)\\, Storhamar") {
+		t.Error("expected the description in the calendar")
+	}
+}
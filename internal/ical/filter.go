@@ -0,0 +1,127 @@
+package ical
+
+import (
+	"strings"
+
+	"github.com/thomasoddsund/hockeykalender/internal/ehl"
+)
+
+// Side restricts a feed to home or away games
+type Side int
+
+const (
+	AllGames Side = iota
+	HomeGames
+	AwayGames
+)
+
+// Sides lists every side, in the order feeds are generated
+var Sides = []Side{AllGames, HomeGames, AwayGames}
+
+// Suffix returns the file name suffix of a side, empty for all games
+func (s Side) Suffix() string {
+	switch s {
+	case HomeGames:
+		return "home"
+	case AwayGames:
+		return "away"
+	}
+	return ""
+}
+
+// ParseSide is the inverse of Suffix for the home and away sides
+func ParseSide(suffix string) (Side, bool) {
+	switch suffix {
+	case "home":
+		return HomeGames, true
+	case "away":
+		return AwayGames, true
+	}
+	return AllGames, false
+}
+
+// label is the Norwegian description of a side in calendar names
+func (s Side) label() string {
+	switch s {
+	case HomeGames:
+		return " (hjemmekamper)"
+	case AwayGames:
+		return " (bortekamper)"
+	}
+	return ""
+}
+
+// Match is how a filter with several teams selects games
+type Match int
+
+const (
+	MatchAny Match = iota // Games involving any of the teams
+	MatchAll              // Games between the teams, e.g. head-to-head
+)
+
+// Filter selects the games of a feed. The zero value selects all games.
+type Filter struct {
+	Teams []string // Team ShortNames
+	Match Match
+	// Side restricts the games to home or away games of any of the teams
+	// with MatchAny, or of the first team with MatchAll
+	Side Side
+	// Venue restricts the games to one venue, matched by UUID, or by name if it has none
+	Venue ehl.Venue
+}
+
+// VenueFilter selects all games at a venue
+func VenueFilter(venue ehl.Venue) Filter {
+	return Filter{Venue: venue}
+}
+
+// TeamFilter selects all games of a single team
+func TeamFilter(teamName string) Filter {
+	return Filter{Teams: []string{teamName}}
+}
+
+// Matches reports whether a game belongs in the feed
+func (f Filter) Matches(game ehl.Game) bool {
+	if f.Venue.UUID != "" && game.Venue.UUID != f.Venue.UUID {
+		return false
+	}
+	if f.Venue.UUID == "" && f.Venue.Name != "" && game.Venue.Name != f.Venue.Name {
+		return false
+	}
+	if len(f.Teams) == 0 {
+		return true
+	}
+
+	matched := 0
+	for i, team := range f.Teams {
+		side := f.Side
+		if f.Match == MatchAll && i > 0 {
+			side = AllGames
+		}
+
+		home := game.HomeTeam.ShortName == team
+		away := game.AwayTeam.ShortName == team
+		if (home && side != AwayGames) || (away && side != HomeGames) {
+			matched++
+		}
+	}
+
+	if f.Match == MatchAll {
+		return matched == len(f.Teams)
+	}
+	return matched > 0
+}
+
+// Name describes the filter in calendar names, e.g. "Vålerenga + Storhamar",
+// "Vålerenga vs Storhamar (hjemmekamper)" or "Jordal Amfi". It is empty for the zero filter.
+func (f Filter) Name() string {
+	if len(f.Teams) == 0 {
+		return f.Venue.Name
+	}
+
+	sep := " + "
+	if f.Match == MatchAll {
+		sep = " vs "
+	}
+	return strings.Join(f.Teams, sep) + f.Side.label()
+}
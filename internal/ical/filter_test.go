@@ -0,0 +1,95 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/thomasoddsund/hockeykalender/internal/ehl"
+)
+
+func TestFilter_Matches(t *testing.T) {
+	games := makeTestGames()
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected []string
+	}{
+		{"zero filter", Filter{}, []string{"game-1", "game-2", "game-3"}},
+		{"team", TeamFilter("Vålerenga"), []string{"game-1", "game-2"}},
+		{"home", Filter{Teams: []string{"Vålerenga"}, Side: HomeGames}, []string{"game-1"}},
+		{"away", Filter{Teams: []string{"Vålerenga"}, Side: AwayGames}, []string{"game-2"}},
+		{"any", Filter{Teams: []string{"Vålerenga", "Frisk Asker"}}, []string{"game-1", "game-2", "game-3"}},
+		{"any home", Filter{Teams: []string{"Storhamar", "Frisk Asker"}, Side: HomeGames}, []string{"game-2", "game-3"}},
+		{"all", Filter{Teams: []string{"Vålerenga", "Storhamar"}, Match: MatchAll}, []string{"game-1", "game-2"}},
+		{"all, first team home", Filter{Teams: []string{"Storhamar", "Vålerenga"}, Match: MatchAll, Side: HomeGames}, []string{"game-2"}},
+		{"all, no meetings", Filter{Teams: []string{"Vålerenga", "Frisk Asker"}, Match: MatchAll}, nil},
+		{"unknown team", TeamFilter("Narvik"), nil},
+		{"venue", VenueFilter(ehl.Venue{UUID: "venue-cc", Name: "CC Amfi"}), []string{"game-2"}},
+		{"venue without UUID", VenueFilter(ehl.Venue{Name: "Askerhallen"}), []string{"game-3"}},
+		{"venue and team", Filter{Teams: []string{"Frisk Asker"}, Venue: ehl.Venue{UUID: "venue-cc"}}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var matched []string
+			for _, game := range games {
+				if tt.filter.Matches(game) {
+					matched = append(matched, game.UUID)
+				}
+			}
+			if strings.Join(matched, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("expected %v, got %v", tt.expected, matched)
+			}
+		})
+	}
+}
+
+func TestFilter_Name(t *testing.T) {
+	tests := []struct {
+		filter   Filter
+		expected string
+	}{
+		{Filter{}, ""},
+		{TeamFilter("Vålerenga"), "Vålerenga"},
+		{Filter{Teams: []string{"Vålerenga"}, Side: HomeGames}, "Vålerenga (hjemmekamper)"},
+		{Filter{Teams: []string{"Vålerenga", "Storhamar"}}, "Vålerenga + Storhamar"},
+		{Filter{Teams: []string{"Vålerenga", "Storhamar"}, Match: MatchAll, Side: AwayGames}, "Vålerenga vs Storhamar (bortekamper)"},
+		{VenueFilter(ehl.Venue{UUID: "venue-jordal", Name: "Jordal Amfi"}), "Jordal Amfi"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.filter.Name(); got != tt.expected {
+			t.Errorf("Name() = %q, want %q", got, tt.expected)
+		}
+	}
+}
+
+func TestGenerateCalendar_Filter(t *testing.T) {
+	games := makeTestGames()
+	filter := Filter{Teams: []string{"Vålerenga", "Storhamar"}, Match: MatchAll, Side: HomeGames}
+
+	result := GenerateCalendar(singleSeason(games), filter, nil, "EHL")
+
+	if count := strings.Count(result, "BEGIN:VEVENT"); count != 1 {
+		t.Errorf("expected 1 event, got %d", count)
+	}
+	if !strings.Contains(result, "X-WR-CALNAME:Vålerenga vs Storhamar (hjemmekamper) - EHL 2025/2026\r\n") {
+		t.Error("expected calendar name to describe the filter")
+	}
+}
+
+func TestParseSide(t *testing.T) {
+	for _, side := range []Side{HomeGames, AwayGames} {
+		parsed, ok := ParseSide(side.Suffix())
+		if !ok || parsed != side {
+			t.Errorf("ParseSide(%q) = %v, %v", side.Suffix(), parsed, ok)
+		}
+	}
+
+	for _, suffix := range []string{"", "all", "1d"} {
+		if _, ok := ParseSide(suffix); ok {
+			t.Errorf("ParseSide(%q) should fail", suffix)
+		}
+	}
+}
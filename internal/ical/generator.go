@@ -2,6 +2,7 @@ package ical
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,83 +17,255 @@ const (
 	UIDDomain = "ehl.hockeykalender"
 )
 
-// GenerateCalendar creates an iCal calendar string from games
-// teamFilter: if non-empty, only include games involving this team (by ShortName)
+// Option configures optional calendar output
+type Option func(*options)
+
+type options struct {
+	loc       *time.Location
+	revisions map[string]Revision
+	links     Links
+	venues    ehl.VenueRegistry
+	context   bool
+	season    *seasonContext // Built by GenerateCalendar if context is set
+}
+
+// Revision tracks when the published data of a game last changed
+type Revision struct {
+	Sequence int       // Incremented on every time or venue change
+	Modified time.Time // Used for DTSTAMP and LAST-MODIFIED
+}
+
+// WithTimeZone writes event times as local times in loc (DTSTART;TZID=...) and
+// includes a VTIMEZONE covering the games. Without it, times are written in UTC.
+func WithTimeZone(loc *time.Location) Option {
+	return func(o *options) {
+		o.loc = loc
+	}
+}
+
+// WithRevisions uses the given revisions, keyed by game UUID, for DTSTAMP, LAST-MODIFIED
+// and SEQUENCE, so unchanged games render identically across runs. Games without a
+// revision are stamped with the current time.
+func WithRevisions(revisions map[string]Revision) Option {
+	return func(o *options) {
+		o.revisions = revisions
+	}
+}
+
+// WithVenues adds the address to the LOCATION of games at venues in the registry,
+// along with GEO and X-APPLE-STRUCTURED-LOCATION properties for navigation apps
+func WithVenues(venues ehl.VenueRegistry) Option {
+	return func(o *options) {
+		o.venues = venues
+	}
+}
+
+// WithSeasonContext adds a DESCRIPTION to games that have not started, with both teams'
+// table position and last five results, and this season's games between them. It is
+// computed from all games passed to GenerateCalendar, not just the filtered ones.
+func WithSeasonContext() Option {
+	return func(o *options) {
+		o.context = true
+	}
+}
+
+// GenerateCalendar creates an iCal calendar string from the games of one or more seasons
+// seasons: game lists to merge into one feed, e.g. the tail of the previous season plus the current one
+// filter: the games to include, e.g. TeamFilter("Vålerenga"); the zero Filter includes all games
 // alarms: list of alarms to add to each event
-// seasonName: the season name to include in the calendar name
-func GenerateCalendar(games []ehl.Game, teamFilter string, alarms []Alarm, seasonName string) string {
-	var sb strings.Builder
+// seriesName: the series name, e.g. "EHL", followed by the season names in the calendar name
+// opts: optional output settings, e.g. WithTimeZone
+func GenerateCalendar(seasons []ehl.SeasonGames, filter Filter, alarms []Alarm, seriesName string, opts ...Option) string {
+	var w contentWriter
 
-	// Filter games if team specified
-	filteredGames := games
-	if teamFilter != "" {
-		filteredGames = filterGamesByTeam(games, teamFilter)
+	o := newOptions(seasons, opts)
+	games := ehl.MergeSeasonGames(seasons)
+
+	var filteredGames []ehl.Game
+	for _, game := range games {
+		if filter.Matches(game) {
+			filteredGames = append(filteredGames, game)
+		}
 	}
 
 	// Calendar header
-	sb.WriteString("BEGIN:VCALENDAR\r\n")
-	sb.WriteString("VERSION:2.0\r\n")
-	sb.WriteString("PRODID:-//Hockeykalender//EHL//NO\r\n")
-	sb.WriteString("CALSCALE:GREGORIAN\r\n")
-	sb.WriteString("METHOD:PUBLISH\r\n")
+	w.Begin("VCALENDAR")
+	w.Raw("VERSION", "2.0")
+	w.Raw("PRODID", "-//Hockeykalender//EHL//NO")
+	w.Raw("CALSCALE", "GREGORIAN")
+	w.Raw("METHOD", "PUBLISH")
 
 	// Calendar name
-	calName := "EHL " + seasonName
-	if teamFilter != "" {
-		calName = teamFilter + " - EHL " + seasonName
+	calName := seriesName + " " + SeasonsName(seasons)
+	if name := filter.Name(); name != "" {
+		calName = name + " - " + calName
+	}
+	w.Text("X-WR-CALNAME", calName)
+
+	if o.loc != nil && len(filteredGames) > 0 {
+		w.Text("X-WR-TIMEZONE", o.loc.String())
+		first, last := ehl.SeasonSpan(filteredGames)
+		writeVTIMEZONE(&w, o.loc, first, last.Add(GameDuration))
 	}
-	sb.WriteString(fmt.Sprintf("X-WR-CALNAME:%s\r\n", calName))
 
 	// Generate events
 	for _, game := range filteredGames {
-		sb.WriteString(formatEvent(game, alarms))
+		formatEvent(&w, game, alarms, o)
 	}
 
-	sb.WriteString("END:VCALENDAR\r\n")
+	w.End("VCALENDAR")
 
-	return sb.String()
+	return w.String()
 }
 
-func filterGamesByTeam(games []ehl.Game, teamName string) []ehl.Game {
-	var filtered []ehl.Game
-	for _, game := range games {
-		if game.HomeTeam.ShortName == teamName || game.AwayTeam.ShortName == teamName {
-			filtered = append(filtered, game)
+// Descriptions returns the DESCRIPTION GenerateCalendar writes with opts for every game of seasons
+// that will be played, keyed by game UUID. With WithSeasonContext it depends on other games, so
+// it is tracked separately from the game data for revisions.
+func Descriptions(seasons []ehl.SeasonGames, opts ...Option) map[string]string {
+	o := newOptions(seasons, opts)
+
+	descriptions := make(map[string]string)
+	for _, game := range ehl.MergeSeasonGames(seasons) {
+		if !game.IsCancelled() {
+			descriptions[game.UUID] = describe(game, o)
 		}
 	}
-	return filtered
+	return descriptions
 }
 
-func formatEvent(game ehl.Game, alarms []Alarm) string {
-	var sb strings.Builder
+func newOptions(seasons []ehl.SeasonGames, opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.context {
+		o.season = newSeasonContext(seasons)
+	}
+	return o
+}
 
-	// Include score in summary if the game has been played (either team has a score)
-	var summary string
-	if game.HomeTeam.Score > 0 || game.AwayTeam.Score > 0 {
-		summary = fmt.Sprintf("%s %d - %d %s", game.HomeTeam.ShortName, game.HomeTeam.Score, game.AwayTeam.Score, game.AwayTeam.ShortName)
-	} else {
-		summary = fmt.Sprintf("%s vs %s", game.HomeTeam.ShortName, game.AwayTeam.ShortName)
+// SeasonsName joins the names of the seasons that have games, e.g. "2024/2025 + 2025/2026".
+// If none of them have games, all season names are used.
+func SeasonsName(seasons []ehl.SeasonGames) string {
+	var names, allNames []string
+	for _, sg := range seasons {
+		allNames = append(allNames, sg.Season.Name)
+		if len(sg.Games) > 0 {
+			names = append(names, sg.Season.Name)
+		}
+	}
+
+	if len(names) == 0 {
+		names = allNames
+	}
+	return strings.Join(names, " + ")
+}
+
+// cancelledPrefix is prepended to the SUMMARY of games that will not be played as scheduled
+var cancelledPrefix = map[ehl.GameState]string{
+	ehl.StateCancelled: "AVLYST: ",
+	ehl.StatePostponed: "UTSATT: ",
+	ehl.StateRemoved:   "AVLYST: ",
+}
+
+// cancelledDescription explains why a game is marked as cancelled
+var cancelledDescription = map[ehl.GameState]string{
+	ehl.StateCancelled: "Kampen er avlyst.",
+	ehl.StatePostponed: "Kampen er utsatt. Ny dato er ikke satt ennå.",
+	ehl.StateRemoved:   "Kampen er fjernet fra terminlisten. Den kan være avlyst eller flyttet.",
+}
+
+func formatEvent(w *contentWriter, game ehl.Game, alarms []Alarm, o options) {
+	// Include the score once the game is live, e.g. "Vålerenga 3 - 2 Storhamar (OT)"
+	summary := fmt.Sprintf("%s vs %s", game.HomeTeam.ShortName, game.AwayTeam.ShortName)
+	if game.State.Started() {
+		summary = result(game)
 	}
 	uid := fmt.Sprintf("%s@%s", game.UUID, UIDDomain)
+	revision, hasRevision := o.revisions[game.UUID]
 	dtstamp := time.Now().UTC().Format("20060102T150405Z")
-	dtstart := game.StartTime.UTC().Format("20060102T150405Z")
-	dtend := game.StartTime.Add(GameDuration).UTC().Format("20060102T150405Z")
+	if hasRevision {
+		dtstamp = revision.Modified.UTC().Format("20060102T150405Z")
+	}
 
-	sb.WriteString("BEGIN:VEVENT\r\n")
-	sb.WriteString(fmt.Sprintf("UID:%s\r\n", uid))
-	sb.WriteString(fmt.Sprintf("DTSTAMP:%s\r\n", dtstamp))
-	sb.WriteString(fmt.Sprintf("DTSTART:%s\r\n", dtstart))
-	sb.WriteString(fmt.Sprintf("DTEND:%s\r\n", dtend))
-	sb.WriteString(fmt.Sprintf("SUMMARY:%s\r\n", summary))
-	sb.WriteString(fmt.Sprintf("LOCATION:%s\r\n", game.Venue))
+	w.Begin("VEVENT")
+	w.Text("UID", uid)
+	w.Raw("DTSTAMP", dtstamp)
+	if hasRevision {
+		w.Raw("LAST-MODIFIED", dtstamp)
+		w.Raw("SEQUENCE", strconv.Itoa(revision.Sequence))
+	}
+	writeTime(w, "DTSTART", game.StartTime, o.loc)
+	writeTime(w, "DTEND", game.StartTime.Add(GameDuration), o.loc)
+	if game.IsCancelled() {
+		w.Text("SUMMARY", cancelledPrefix[game.State]+summary)
+		w.Raw("STATUS", "CANCELLED")
+		w.Text("DESCRIPTION", cancelledDescription[game.State])
+	} else {
+		w.Text("SUMMARY", summary)
+		if description := describe(game, o); description != "" {
+			w.Text("DESCRIPTION", description)
+		}
+	}
+	writeLocation(w, game.Venue, o.venues)
+	if gameURL := o.links.GameURL(game); gameURL != "" {
+		w.Raw("URL", gameURL)
+	}
+	if game.GameType.Name != "" {
+		w.Text("CATEGORIES", game.GameType.Name)
+	}
 
-	// Add alarms
-	for _, alarm := range alarms {
-		sb.WriteString(FormatVALARM(alarm, summary))
-		sb.WriteString("\r\n")
+	// Add alarms, except for games that will not be played
+	if !game.IsCancelled() {
+		for _, alarm := range alarms {
+			writeVALARM(w, alarm, summary)
+		}
+	}
+
+	w.End("VEVENT")
+}
+
+// describe returns the DESCRIPTION of a game that will be played: the season context
+// of upcoming games and the venue link
+func describe(game ehl.Game, o options) string {
+	var lines []string
+	if o.season != nil && !game.State.Started() {
+		if context := o.season.describe(game); context != "" {
+			lines = append(lines, context)
+		}
+	}
+	if venueURL := o.links.VenueURL(game); venueURL != "" {
+		lines = append(lines, fmt.Sprintf("Veibeskrivelse til %s: %s", game.Venue.Name, venueURL))
+	}
+	return strings.Join(lines, "\n\n")
+}
+
+// writeLocation writes the LOCATION of a venue, with its address and coordinates if it is in venues
+func writeLocation(w *contentWriter, venue ehl.Venue, venues ehl.VenueRegistry) {
+	details, ok := venues.Lookup(venue)
+	if !ok {
+		w.Text("LOCATION", venue.Name)
+		return
 	}
 
-	sb.WriteString("END:VEVENT\r\n")
+	address := details.FullAddress()
+	w.Text("LOCATION", venue.Name+", "+address)
+	if !details.HasCoordinates() {
+		return
+	}
 
-	return sb.String()
+	lat := strconv.FormatFloat(details.Latitude, 'f', 6, 64)
+	lon := strconv.FormatFloat(details.Longitude, 'f', 6, 64)
+	w.Raw("GEO", lat+";"+lon)
+	w.Raw("X-APPLE-STRUCTURED-LOCATION;VALUE=URI;X-ADDRESS="+QuoteParam(address)+
+		";X-APPLE-RADIUS=100;X-TITLE="+QuoteParam(venue.Name), "geo:"+lat+","+lon)
+}
+
+// writeTime writes a DATE-TIME property in UTC, or as local time with a TZID if loc is set
+func writeTime(w *contentWriter, name string, t time.Time, loc *time.Location) {
+	if loc == nil {
+		w.Raw(name, t.UTC().Format("20060102T150405Z"))
+		return
+	}
+	w.Raw(name+";TZID="+loc.String(), t.In(loc).Format(localTimeFormat))
 }
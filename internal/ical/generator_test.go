@@ -24,7 +24,7 @@ func makeTestGames() []ehl.Game {
 				Code:      "STH",
 				ShortName: "Storhamar",
 			},
-			Venue: "Jordal Amfi",
+			Venue: ehl.Venue{UUID: "venue-jordal", Name: "Jordal Amfi"},
 		},
 		{
 			UUID:      "game-2",
@@ -40,7 +40,7 @@ func makeTestGames() []ehl.Game {
 				Code:      "VIF",
 				ShortName: "Vålerenga",
 			},
-			Venue: "CC Amfi",
+			Venue: ehl.Venue{UUID: "venue-cc", Name: "CC Amfi"},
 		},
 		{
 			UUID:      "game-3",
@@ -56,15 +56,20 @@ func makeTestGames() []ehl.Game {
 				Code:      "OIL",
 				ShortName: "Stavanger Oilers",
 			},
-			Venue: "Askerhallen",
+			Venue: ehl.Venue{UUID: "venue-asker", Name: "Askerhallen"},
 		},
 	}
 }
 
+// singleSeason wraps games as the only season of a calendar
+func singleSeason(games []ehl.Game) []ehl.SeasonGames {
+	return []ehl.SeasonGames{{Season: ehl.Season{UUID: "season-2526", Name: "2025/2026"}, Games: games}}
+}
+
 func TestGenerateCalendar_BasicStructure(t *testing.T) {
 	games := makeTestGames()
 
-	result := GenerateCalendar(games, "", []Alarm{}, "2025/2026")
+	result := GenerateCalendar(singleSeason(games), Filter{}, []Alarm{}, "EHL")
 
 	// Check header
 	if !strings.HasPrefix(result, "BEGIN:VCALENDAR") {
@@ -84,7 +89,7 @@ func TestGenerateCalendar_BasicStructure(t *testing.T) {
 func TestGenerateCalendar_AllGames(t *testing.T) {
 	games := makeTestGames()
 
-	result := GenerateCalendar(games, "", []Alarm{}, "2025/2026")
+	result := GenerateCalendar(singleSeason(games), Filter{}, []Alarm{}, "EHL")
 
 	// Should contain all 3 games
 	count := strings.Count(result, "BEGIN:VEVENT")
@@ -101,7 +106,7 @@ func TestGenerateCalendar_AllGames(t *testing.T) {
 func TestGenerateCalendar_FilterByTeam(t *testing.T) {
 	games := makeTestGames()
 
-	result := GenerateCalendar(games, "Vålerenga", []Alarm{}, "2025/2026")
+	result := GenerateCalendar(singleSeason(games), TeamFilter("Vålerenga"), []Alarm{}, "EHL")
 
 	// Should only contain games involving Vålerenga (2 games)
 	count := strings.Count(result, "BEGIN:VEVENT")
@@ -118,7 +123,7 @@ func TestGenerateCalendar_FilterByTeam(t *testing.T) {
 func TestGenerateCalendar_EventContent(t *testing.T) {
 	games := makeTestGames()[:1] // Just first game
 
-	result := GenerateCalendar(games, "", []Alarm{}, "2025/2026")
+	result := GenerateCalendar(singleSeason(games), Filter{}, []Alarm{}, "EHL")
 
 	// Check UID format
 	if !strings.Contains(result, "UID:game-1@ehl.hockeykalender") {
@@ -149,7 +154,7 @@ func TestGenerateCalendar_EventContent(t *testing.T) {
 func TestGenerateCalendar_WithAlarms(t *testing.T) {
 	games := makeTestGames()[:1]
 
-	result := GenerateCalendar(games, "", []Alarm{Alarm1Day, Alarm1Hour}, "2025/2026")
+	result := GenerateCalendar(singleSeason(games), Filter{}, []Alarm{Alarm1Day, Alarm1Hour}, "EHL")
 
 	// Should have 2 alarms
 	alarmCount := strings.Count(result, "BEGIN:VALARM")
@@ -168,7 +173,7 @@ func TestGenerateCalendar_WithAlarms(t *testing.T) {
 func TestGenerateCalendar_NoAlarms(t *testing.T) {
 	games := makeTestGames()[:1]
 
-	result := GenerateCalendar(games, "", []Alarm{}, "2025/2026")
+	result := GenerateCalendar(singleSeason(games), Filter{}, []Alarm{}, "EHL")
 
 	// Should have no alarms
 	if strings.Contains(result, "BEGIN:VALARM") {
@@ -179,7 +184,7 @@ func TestGenerateCalendar_NoAlarms(t *testing.T) {
 func TestGenerateCalendar_LineEndings(t *testing.T) {
 	games := makeTestGames()[:1]
 
-	result := GenerateCalendar(games, "", []Alarm{}, "2025/2026")
+	result := GenerateCalendar(singleSeason(games), Filter{}, []Alarm{}, "EHL")
 
 	// iCal spec requires CRLF line endings
 	if !strings.Contains(result, "\r\n") {
@@ -206,14 +211,197 @@ func TestGenerateCalendar_WithScores(t *testing.T) {
 				ShortName: "Storhamar",
 				Score:     2,
 			},
-			Venue: "Jordal Amfi",
+			Venue: ehl.Venue{Name: "Jordal Amfi"},
 		},
 	}
 
-	result := GenerateCalendar(games, "", []Alarm{}, "2025/2026")
+	result := GenerateCalendar(singleSeason(games), Filter{}, []Alarm{}, "EHL")
 
 	// Check SUMMARY includes the score
 	if !strings.Contains(result, "SUMMARY:Vålerenga 4 - 2 Storhamar") {
 		t.Error("expected SUMMARY to include score: Vålerenga 4 - 2 Storhamar")
 	}
 }
+
+func TestGenerateCalendar_ResultSummary(t *testing.T) {
+	tests := []struct {
+		name     string
+		state    ehl.GameState
+		decision ehl.Decision
+		home     int
+		away     int
+		expected string
+	}{
+		{"not started", ehl.StatePreGame, "", 0, 0, "SUMMARY:Vålerenga vs Storhamar\r\n"},
+		{"live scoreless", ehl.StateLive, "", 0, 0, "SUMMARY:Vålerenga 0 - 0 Storhamar (live)\r\n"},
+		{"live", ehl.StateLive, "", 1, 0, "SUMMARY:Vålerenga 1 - 0 Storhamar (live)\r\n"},
+		{"regulation", ehl.StatePostGame, ehl.DecisionRegulation, 4, 2, "SUMMARY:Vålerenga 4 - 2 Storhamar\r\n"},
+		{"overtime", ehl.StatePostGame, ehl.DecisionOvertime, 3, 2, "SUMMARY:Vålerenga 3 - 2 Storhamar (OT)\r\n"},
+		{"scoreless shootout", ehl.StatePostGame, ehl.DecisionShootout, 0, 0, "SUMMARY:Vålerenga 0 - 0 Storhamar (SO)\r\n"},
+		{"postponed", ehl.StatePostponed, "", 0, 0, "SUMMARY:UTSATT: Vålerenga vs Storhamar\r\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := makeTestGames()[0]
+			game.State = tt.state
+			game.Decision = tt.decision
+			game.HomeTeam.Score = tt.home
+			game.AwayTeam.Score = tt.away
+
+			result := GenerateCalendar(singleSeason([]ehl.Game{game}), Filter{}, nil, "EHL")
+			if !strings.Contains(result, tt.expected) {
+				t.Errorf("expected %q in:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestGenerateCalendar_GameTypeCategory(t *testing.T) {
+	games := makeTestGames()[:1]
+	games[0].GameType = ehl.GameType{UUID: "playoffs", Name: "Sluttspill"}
+
+	result := GenerateCalendar(singleSeason(games), Filter{}, []Alarm{}, "EHL")
+
+	if !strings.Contains(result, "CATEGORIES:Sluttspill") {
+		t.Error("expected CATEGORIES:Sluttspill")
+	}
+}
+
+func TestGenerateCalendar_MultipleSeasons(t *testing.T) {
+	previous := ehl.SeasonGames{
+		Season: ehl.Season{UUID: "season-2425", Name: "2024/2025"},
+		Games: []ehl.Game{
+			{
+				UUID:      "game-final",
+				StartTime: time.Date(2025, 4, 10, 17, 0, 0, 0, time.UTC),
+				HomeTeam:  ehl.Team{ShortName: "Storhamar"},
+				AwayTeam:  ehl.Team{ShortName: "Vålerenga"},
+			},
+		},
+	}
+	current := ehl.SeasonGames{
+		Season: ehl.Season{UUID: "season-2526", Name: "2025/2026"},
+		Games:  makeTestGames(),
+	}
+
+	result := GenerateCalendar([]ehl.SeasonGames{previous, current}, TeamFilter("Vålerenga"), []Alarm{}, "EHL")
+
+	if count := strings.Count(result, "BEGIN:VEVENT"); count != 3 {
+		t.Errorf("expected 3 events across both seasons, got %d", count)
+	}
+	if !strings.Contains(result, "X-WR-CALNAME:Vålerenga - EHL 2024/2025 + 2025/2026") {
+		t.Error("expected calendar name to include both seasons")
+	}
+
+	// Events are ordered by start time regardless of season order
+	if strings.Index(result, "UID:game-final@") > strings.Index(result, "UID:game-1@") {
+		t.Error("expected previous season's game first")
+	}
+}
+
+func TestSeasonsName(t *testing.T) {
+	played := []ehl.Game{{UUID: "game-1"}}
+
+	tests := []struct {
+		name     string
+		seasons  []ehl.SeasonGames
+		expected string
+	}{
+		{"single", []ehl.SeasonGames{{Season: ehl.Season{Name: "2025/2026"}, Games: played}}, "2025/2026"},
+		{"overlap", []ehl.SeasonGames{
+			{Season: ehl.Season{Name: "2024/2025"}, Games: played},
+			{Season: ehl.Season{Name: "2025/2026"}, Games: played},
+		}, "2024/2025 + 2025/2026"},
+		{"previous season ended", []ehl.SeasonGames{
+			{Season: ehl.Season{Name: "2024/2025"}},
+			{Season: ehl.Season{Name: "2025/2026"}, Games: played},
+		}, "2025/2026"},
+		{"no games", []ehl.SeasonGames{{Season: ehl.Season{Name: "2026/2027"}}}, "2026/2027"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SeasonsName(tt.seasons); got != tt.expected {
+				t.Errorf("SeasonsName() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateCalendar_WithRevisions(t *testing.T) {
+	games := makeTestGames()[:2]
+	revisions := map[string]Revision{
+		"game-1": {Sequence: 2, Modified: time.Date(2025, 9, 1, 6, 0, 0, 0, time.UTC)},
+	}
+
+	result := GenerateCalendar(singleSeason(games), Filter{}, nil, "EHL", WithRevisions(revisions))
+
+	for _, e := range []string{"DTSTAMP:20250901T060000Z", "LAST-MODIFIED:20250901T060000Z", "SEQUENCE:2"} {
+		if !strings.Contains(result, e) {
+			t.Errorf("expected %s", e)
+		}
+	}
+
+	// game-2 has no revision
+	if count := strings.Count(result, "SEQUENCE:"); count != 1 {
+		t.Errorf("expected SEQUENCE only for the game with a revision, got %d", count)
+	}
+}
+
+func TestGenerateCalendar_CancelledGames(t *testing.T) {
+	games := makeTestGames()
+	games[0].State = ehl.StateRemoved
+	games[1].State = ehl.StatePostponed
+
+	result := GenerateCalendar(singleSeason(games), Filter{}, []Alarm{Alarm1Hour}, "EHL")
+
+	if count := strings.Count(result, "STATUS:CANCELLED"); count != 2 {
+		t.Errorf("expected 2 cancelled events, got %d", count)
+	}
+	if !strings.Contains(result, "SUMMARY:AVLYST: Vålerenga vs Storhamar") {
+		t.Error("expected removed game to be marked AVLYST")
+	}
+	if !strings.Contains(result, "SUMMARY:UTSATT: Storhamar vs Vålerenga") {
+		t.Error("expected postponed game to be marked UTSATT")
+	}
+	if !strings.Contains(result, "DESCRIPTION:Kampen er fjernet fra terminlisten.") {
+		t.Error("expected explanation for removed game")
+	}
+
+	// Only the game that will be played keeps its alarm
+	if count := strings.Count(result, "BEGIN:VALARM"); count != 1 {
+		t.Errorf("expected 1 alarm, got %d", count)
+	}
+}
+
+func TestGenerateCalendar_WithVenues(t *testing.T) {
+	games := makeTestGames()[:3]
+	games[1].Venue = ehl.Venue{UUID: "venue-unknown", Name: "Ukjent hall"}
+	games[2].Venue = ehl.Venue{UUID: "venue-city", Name: "Byhallen"}
+	venues := ehl.VenueRegistry{
+		"venue-jordal": {Name: "Jordal Amfi", Address: "Storgata 1", PostalCode: "0155", City: "Oslo", Latitude: 59.9115, Longitude: 10.7859},
+		"Byhallen":     {Name: "Byhallen", City: "Hamar"},
+	}
+
+	result := GenerateCalendar(singleSeason(games), Filter{}, nil, "EHL", WithVenues(venues))
+	unfolded := strings.ReplaceAll(result, "\r\n ", "")
+
+	expected := []string{
+		`LOCATION:Jordal Amfi\, Storgata 1\, 0155 Oslo` + "\r\n",
+		"GEO:59.911500;10.785900\r\n",
+		`X-APPLE-STRUCTURED-LOCATION;VALUE=URI;X-ADDRESS="Storgata 1, 0155 Oslo";X-APPLE-RADIUS=100;X-TITLE="Jordal Amfi":geo:59.911500,10.785900` + "\r\n",
+		// Unknown venues keep the plain name
+		"LOCATION:Ukjent hall\r\n",
+		// Venues without coordinates only get the locality
+		`LOCATION:Byhallen\, Hamar` + "\r\n",
+	}
+	for _, s := range expected {
+		if !strings.Contains(unfolded, s) {
+			t.Errorf("expected %q in:\n%s", s, unfolded)
+		}
+	}
+	if count := strings.Count(unfolded, "GEO:"); count != 1 {
+		t.Errorf("expected GEO only for the known venue, got %d", count)
+	}
+}
@@ -0,0 +1,80 @@
+package ical
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/thomasoddsund/hockeykalender/internal/ehl"
+)
+
+// Links holds the URL templates of the links added to events. Placeholders are
+// replaced by URL-escaped values: {game} is the game UUID, {venue} the venue name
+// and {venueUUID} the venue UUID. An empty template adds no link.
+type Links struct {
+	Game  string // Game page, written as the URL property
+	Venue string // Map of the venue, linked in the DESCRIPTION
+}
+
+// DefaultLinks link to the game page on ehl.no and the venue on Google Maps
+var DefaultLinks = Links{
+	Game:  "https://www.ehl.no/kamp/{game}",
+	Venue: "https://www.google.com/maps/search/?api=1&query={venue}",
+}
+
+var placeholderPattern = regexp.MustCompile(`\{[^{}]*\}`)
+
+// knownPlaceholders are the placeholders of link templates
+var knownPlaceholders = map[string]bool{"{game}": true, "{venue}": true, "{venueUUID}": true}
+
+// Validate checks that the templates are absolute http(s) URLs with known placeholders
+func (l Links) Validate() error {
+	for _, template := range []string{l.Game, l.Venue} {
+		if template == "" {
+			continue
+		}
+		for _, placeholder := range placeholderPattern.FindAllString(template, -1) {
+			if !knownPlaceholders[placeholder] {
+				return fmt.Errorf("unknown placeholder %s in %q", placeholder, template)
+			}
+		}
+
+		u, err := url.Parse(placeholderPattern.ReplaceAllString(template, "x"))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid link template %q: must be an http or https URL", template)
+		}
+	}
+	return nil
+}
+
+// GameURL returns the link to a game's page, or "" if there is no game template
+func (l Links) GameURL(game ehl.Game) string {
+	return expand(l.Game, game)
+}
+
+// VenueURL returns the link to a game's venue, or "" if there is no venue template or venue
+func (l Links) VenueURL(game ehl.Game) string {
+	if game.Venue.Name == "" {
+		return ""
+	}
+	return expand(l.Venue, game)
+}
+
+func expand(template string, game ehl.Game) string {
+	if template == "" {
+		return ""
+	}
+	return strings.NewReplacer(
+		"{game}", url.PathEscape(game.UUID),
+		"{venue}", url.QueryEscape(game.Venue.Name),
+		"{venueUUID}", url.PathEscape(game.Venue.UUID),
+	).Replace(template)
+}
+
+// WithLinks adds a URL property and a venue link in the DESCRIPTION to every event
+func WithLinks(links Links) Option {
+	return func(o *options) {
+		o.links = links
+	}
+}
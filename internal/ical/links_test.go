@@ -0,0 +1,77 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/thomasoddsund/hockeykalender/internal/ehl"
+)
+
+func TestLinks_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		links   Links
+		wantErr bool
+	}{
+		{"defaults", DefaultLinks, false},
+		{"disabled", Links{}, false},
+		{"venue UUID", Links{Venue: "https://example.com/arena/{venueUUID}"}, false},
+		{"unknown placeholder", Links{Game: "https://example.com/{match}"}, true},
+		{"relative", Links{Game: "/kamp/{game}"}, true},
+		{"not http", Links{Venue: "geo:{venue}"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.links.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLinks_URLs(t *testing.T) {
+	game := ehl.Game{UUID: "abc/123", Venue: ehl.Venue{UUID: "venue-cc", Name: "CC Amfi, Hamar & Co"}}
+	links := Links{
+		Game:  "https://example.com/kamp/{game}",
+		Venue: "https://maps.example.com/?q={venue}&id={venueUUID}",
+	}
+
+	if got := links.GameURL(game); got != "https://example.com/kamp/abc%2F123" {
+		t.Errorf("GameURL() = %s", got)
+	}
+	if got := links.VenueURL(game); got != "https://maps.example.com/?q=CC+Amfi%2C+Hamar+%26+Co&id=venue-cc" {
+		t.Errorf("VenueURL() = %s", got)
+	}
+
+	game.Venue = ehl.Venue{}
+	if got := links.VenueURL(game); got != "" {
+		t.Errorf("expected no venue link without a venue, got %s", got)
+	}
+	if got := (Links{}).GameURL(game); got != "" {
+		t.Errorf("expected no game link without a template, got %s", got)
+	}
+}
+
+func TestGenerateCalendar_WithLinks(t *testing.T) {
+	games := makeTestGames()
+	games[1].State = ehl.StateCancelled
+
+	result := GenerateCalendar(singleSeason(games[:2]), Filter{}, nil, "EHL", WithLinks(DefaultLinks))
+	unfolded := strings.ReplaceAll(result, "\r\n ", "")
+
+	expected := []string{
+		"URL:https://www.ehl.no/kamp/game-1\r\n",
+		`DESCRIPTION:Veibeskrivelse til Jordal Amfi: https://www.google.com/maps/search/?api=1&query=Jordal+Amfi` + "\r\n",
+		// Cancelled games keep their explanation without a venue link
+		"DESCRIPTION:Kampen er avlyst.\r\n",
+	}
+	for _, s := range expected {
+		if !strings.Contains(unfolded, s) {
+			t.Errorf("expected %q in:\n%s", s, unfolded)
+		}
+	}
+	if count := strings.Count(unfolded, "URL:"); count != 2 {
+		t.Errorf("expected a URL on both events, got %d", count)
+	}
+}
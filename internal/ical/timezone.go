@@ -0,0 +1,74 @@
+package ical
+
+import (
+	"fmt"
+	"time"
+	_ "time/tzdata" // Embedded so VTIMEZONE generation does not depend on the host's zoneinfo
+)
+
+const (
+	// DefaultTimeZone is the zone used for local event times
+	DefaultTimeZone = "Europe/Oslo"
+
+	localTimeFormat = "20060102T150405"
+)
+
+// writeVTIMEZONE writes a VTIMEZONE for loc with one observance per UTC offset
+// transition between from and to, starting with the observance in effect at from
+func writeVTIMEZONE(w *contentWriter, loc *time.Location, from, to time.Time) {
+	w.Begin("VTIMEZONE")
+	w.Raw("TZID", loc.String())
+
+	t := from.In(loc)
+	start, end := t.ZoneBounds()
+	if start.IsZero() {
+		// No transitions before from, e.g. a fixed zone
+		name, offset := t.Zone()
+		writeObservance(w, "STANDARD", time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), name, offset, offset)
+	} else {
+		writeTransition(w, loc, start)
+	}
+
+	for !end.IsZero() && end.Before(to) {
+		writeTransition(w, loc, end)
+		_, end = end.In(loc).ZoneBounds()
+	}
+
+	w.End("VTIMEZONE")
+}
+
+// writeTransition writes the observance that starts at the transition instant at
+func writeTransition(w *contentWriter, loc *time.Location, at time.Time) {
+	_, offsetFrom := at.Add(-time.Second).In(loc).Zone()
+	name, offsetTo := at.In(loc).Zone()
+
+	kind := "STANDARD"
+	if at.In(loc).IsDST() {
+		kind = "DAYLIGHT"
+	}
+
+	// DTSTART is the local time of the onset in the offset in effect before it
+	onset := at.In(time.FixedZone("", offsetFrom))
+	writeObservance(w, kind, onset, name, offsetFrom, offsetTo)
+}
+
+func writeObservance(w *contentWriter, kind string, onset time.Time, name string, offsetFrom, offsetTo int) {
+	w.Begin(kind)
+	w.Raw("DTSTART", onset.Format(localTimeFormat))
+	w.Raw("TZOFFSETFROM", formatOffset(offsetFrom))
+	w.Raw("TZOFFSETTO", formatOffset(offsetTo))
+	if name != "" {
+		w.Text("TZNAME", name)
+	}
+	w.End(kind)
+}
+
+// formatOffset formats a UTC offset in seconds as +HHMM
+func formatOffset(seconds int) string {
+	sign := '+'
+	if seconds < 0 {
+		sign = '-'
+		seconds = -seconds
+	}
+	return fmt.Sprintf("%c%02d%02d", sign, seconds/3600, seconds%3600/60)
+}
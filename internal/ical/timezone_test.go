@@ -0,0 +1,143 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thomasoddsund/hockeykalender/internal/ehl"
+)
+
+func oslo(t *testing.T) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(DefaultTimeZone)
+	if err != nil {
+		t.Fatalf("failed to load %s: %v", DefaultTimeZone, err)
+	}
+	return loc
+}
+
+// dstGames returns games that straddle the DST changes of the 2025/2026 season
+func dstGames() []ehl.Game {
+	return []ehl.Game{
+		{
+			// 02:30 CEST, ends 03:30 CET after clocks go back at 03:00 CEST
+			UUID:      "game-dst-end",
+			StartTime: time.Date(2025, 10, 26, 0, 30, 0, 0, time.UTC),
+			HomeTeam:  ehl.Team{ShortName: "Vålerenga"},
+			AwayTeam:  ehl.Team{ShortName: "Storhamar"},
+		},
+		{
+			// 01:30 CET, ends 04:30 CEST after clocks go forward at 02:00 CET
+			UUID:      "game-dst-start",
+			StartTime: time.Date(2026, 3, 29, 0, 30, 0, 0, time.UTC),
+			HomeTeam:  ehl.Team{ShortName: "Storhamar"},
+			AwayTeam:  ehl.Team{ShortName: "Vålerenga"},
+		},
+	}
+}
+
+func TestGenerateCalendar_TimeZoneEventTimes(t *testing.T) {
+	result := GenerateCalendar(singleSeason(dstGames()), Filter{}, nil, "EHL", WithTimeZone(oslo(t)))
+
+	expected := []string{
+		"DTSTART;TZID=Europe/Oslo:20251026T023000",
+		"DTEND;TZID=Europe/Oslo:20251026T033000",
+		"DTSTART;TZID=Europe/Oslo:20260329T013000",
+		"DTEND;TZID=Europe/Oslo:20260329T043000",
+		"X-WR-TIMEZONE:Europe/Oslo",
+	}
+	for _, e := range expected {
+		if !strings.Contains(result, e) {
+			t.Errorf("expected %s", e)
+		}
+	}
+
+	if strings.Contains(result, "DTSTART:20251026T003000Z") {
+		t.Error("expected no UTC DTSTART when a time zone is set")
+	}
+}
+
+func TestGenerateCalendar_VTIMEZONE(t *testing.T) {
+	result := GenerateCalendar(singleSeason(dstGames()), Filter{}, nil, "EHL", WithTimeZone(oslo(t)))
+
+	start := strings.Index(result, "BEGIN:VTIMEZONE")
+	end := strings.Index(result, "END:VTIMEZONE")
+	if start < 0 || end < 0 {
+		t.Fatal("expected a VTIMEZONE")
+	}
+	if start > strings.Index(result, "BEGIN:VEVENT") {
+		t.Error("expected VTIMEZONE before the events")
+	}
+
+	vtimezone := result[start:end]
+	expected := strings.Join([]string{
+		"BEGIN:VTIMEZONE",
+		"TZID:Europe/Oslo",
+		// In effect when the first game starts
+		"BEGIN:DAYLIGHT",
+		"DTSTART:20250330T020000",
+		"TZOFFSETFROM:+0100",
+		"TZOFFSETTO:+0200",
+		"TZNAME:CEST",
+		"END:DAYLIGHT",
+		"BEGIN:STANDARD",
+		"DTSTART:20251026T030000",
+		"TZOFFSETFROM:+0200",
+		"TZOFFSETTO:+0100",
+		"TZNAME:CET",
+		"END:STANDARD",
+		"BEGIN:DAYLIGHT",
+		"DTSTART:20260329T020000",
+		"TZOFFSETFROM:+0100",
+		"TZOFFSETTO:+0200",
+		"TZNAME:CEST",
+		"END:DAYLIGHT",
+		"",
+	}, "\r\n")
+	if vtimezone != expected {
+		t.Errorf("unexpected VTIMEZONE:\n%s\nwant:\n%s", vtimezone, expected)
+	}
+}
+
+func TestGenerateCalendar_VTIMEZONEWithinOnePeriod(t *testing.T) {
+	games := makeTestGames() // All in September 2025
+
+	result := GenerateCalendar(singleSeason(games), Filter{}, nil, "EHL", WithTimeZone(oslo(t)))
+
+	if count := strings.Count(result, "BEGIN:DAYLIGHT") + strings.Count(result, "BEGIN:STANDARD"); count != 1 {
+		t.Errorf("expected 1 observance, got %d", count)
+	}
+	if !strings.Contains(result, "DTSTART;TZID=Europe/Oslo:20250911T190000") {
+		t.Error("expected 19:00 CEST start time")
+	}
+}
+
+func TestGenerateCalendar_NoTimeZone(t *testing.T) {
+	result := GenerateCalendar(singleSeason(dstGames()), Filter{}, nil, "EHL")
+
+	if strings.Contains(result, "VTIMEZONE") || strings.Contains(result, "TZID") {
+		t.Error("expected UTC output without a time zone option")
+	}
+	if !strings.Contains(result, "DTSTART:20251026T003000Z") {
+		t.Error("expected UTC DTSTART")
+	}
+}
+
+func TestFormatOffset(t *testing.T) {
+	tests := []struct {
+		seconds  int
+		expected string
+	}{
+		{3600, "+0100"},
+		{7200, "+0200"},
+		{-12600, "-0330"},
+		{0, "+0000"},
+	}
+
+	for _, tt := range tests {
+		if got := formatOffset(tt.seconds); got != tt.expected {
+			t.Errorf("formatOffset(%d) = %s, want %s", tt.seconds, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/thomasoddsund/hockeykalender/internal/ehl"
+	"github.com/thomasoddsund/hockeykalender/internal/ical"
+)
+
+const (
+	anySeparator = "+"
+	allSeparator = "-vs-"
+)
+
+// FeedSpec is a feed selected by team slugs, the URL form of an ical.Filter:
+//
+//	valerenga               one team
+//	valerenga+storhamar     games of any of the teams
+//	valerenga-vs-storhamar  games between the teams
+//	valerenga-home          with -home or -away, only home or away games
+type FeedSpec struct {
+	Slugs []string
+	Match ical.Match
+	Side  ical.Side
+}
+
+// TeamSpec is the spec of a single team's feed
+func TeamSpec(slug string, side ical.Side) FeedSpec {
+	return FeedSpec{Slugs: []string{slug}, Side: side}
+}
+
+// Slug returns the file name slug of the spec, see Filename
+func (s FeedSpec) Slug() string {
+	sep := anySeparator
+	if s.Match == ical.MatchAll {
+		sep = allSeparator
+	}
+
+	slug := strings.Join(s.Slugs, sep)
+	if suffix := s.Side.Suffix(); suffix != "" {
+		slug += "-" + suffix
+	}
+	return slug
+}
+
+// ParseFeedSlug is the inverse of Slug, for slugs from ParseFilename
+func ParseFeedSlug(slug string) (FeedSpec, error) {
+	var spec FeedSpec
+
+	if i := strings.LastIndex(slug, "-"); i > 0 {
+		if side, ok := ical.ParseSide(slug[i+1:]); ok {
+			spec.Side = side
+			slug = slug[:i]
+		}
+	}
+
+	hasAny := strings.Contains(slug, anySeparator)
+	hasAll := strings.Contains(slug, allSeparator)
+	switch {
+	case hasAny && hasAll:
+		return FeedSpec{}, fmt.Errorf("invalid feed %q: cannot combine %q and %q", slug, anySeparator, allSeparator)
+	case hasAll:
+		spec.Match = ical.MatchAll
+		spec.Slugs = strings.Split(slug, allSeparator)
+	default:
+		spec.Slugs = strings.Split(slug, anySeparator)
+	}
+
+	seen := make(map[string]bool, len(spec.Slugs))
+	for _, s := range spec.Slugs {
+		if s == "" || seen[s] || strings.HasPrefix(s, "-") || strings.HasSuffix(s, "-") {
+			return FeedSpec{}, fmt.Errorf("invalid feed %q", slug)
+		}
+		seen[s] = true
+	}
+
+	return spec, nil
+}
+
+// ParseFeedSpecs parses a comma-separated list of feed slugs, e.g. "valerenga-vs-storhamar,valerenga+storhamar"
+func ParseFeedSpecs(list string) ([]FeedSpec, error) {
+	var specs []FeedSpec
+	for _, slug := range strings.Split(list, ",") {
+		spec, err := ParseFeedSlug(strings.TrimSpace(slug))
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// Filter resolves the slugs of the spec to a filter, using teams keyed by slug
+func (s FeedSpec) Filter(teams map[string]ehl.Team) (ical.Filter, error) {
+	filter := ical.Filter{Match: s.Match, Side: s.Side}
+	for _, slug := range s.Slugs {
+		team, ok := teams[slug]
+		if !ok {
+			return ical.Filter{}, fmt.Errorf("unknown team %q", slug)
+		}
+		filter.Teams = append(filter.Teams, team.ShortName)
+	}
+	return filter, nil
+}
+
+// TeamsBySlug indexes teams by their slug, for FeedSpec.Filter
+func TeamsBySlug(teams []ehl.Team) map[string]ehl.Team {
+	bySlug := make(map[string]ehl.Team, len(teams))
+	for _, team := range teams {
+		bySlug[team.Slug()] = team
+	}
+	return bySlug
+}
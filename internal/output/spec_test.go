@@ -0,0 +1,93 @@
+package output
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/thomasoddsund/hockeykalender/internal/ehl"
+	"github.com/thomasoddsund/hockeykalender/internal/ical"
+)
+
+func TestFeedSpec_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     FeedSpec
+		alarms   []ical.Alarm
+		filename string
+	}{
+		{"team", TeamSpec("valerenga", ical.AllGames), nil, "valerenga.ics"},
+		{"home", TeamSpec("valerenga", ical.HomeGames), nil, "valerenga-home.ics"},
+		{"away with alarms", TeamSpec("frisk-asker", ical.AwayGames), []ical.Alarm{ical.Alarm1Day, ical.Alarm1Hour}, "frisk-asker-away-1d-1h.ics"},
+		{"any", FeedSpec{Slugs: []string{"valerenga", "storhamar"}}, nil, "valerenga+storhamar.ics"},
+		{"three teams", FeedSpec{Slugs: []string{"valerenga", "frisk-asker", "storhamar"}}, []ical.Alarm{ical.Alarm1Hour}, "valerenga+frisk-asker+storhamar-1h.ics"},
+		{"head-to-head", FeedSpec{Slugs: []string{"valerenga", "storhamar"}, Match: ical.MatchAll}, nil, "valerenga-vs-storhamar.ics"},
+		{"head-to-head home", FeedSpec{Slugs: []string{"frisk-asker", "storhamar"}, Match: ical.MatchAll, Side: ical.HomeGames}, []ical.Alarm{ical.Alarm1Day}, "frisk-asker-vs-storhamar-home-1d.ics"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := Filename(tt.spec.Slug(), tt.alarms)
+			if filename != tt.filename {
+				t.Fatalf("expected %s, got %s", tt.filename, filename)
+			}
+
+			slug, alarms, err := ParseFilename(filename)
+			if err != nil {
+				t.Fatalf("ParseFilename failed: %v", err)
+			}
+			spec, err := ParseFeedSlug(slug)
+			if err != nil {
+				t.Fatalf("ParseFeedSlug failed: %v", err)
+			}
+			if !slices.Equal(spec.Slugs, tt.spec.Slugs) || spec.Match != tt.spec.Match || spec.Side != tt.spec.Side || len(alarms) != len(tt.alarms) {
+				t.Errorf("round trip gave %+v, %v", spec, alarms)
+			}
+		})
+	}
+}
+
+func TestParseFeedSlug_Invalid(t *testing.T) {
+	for _, slug := range []string{
+		"valerenga+storhamar-vs-narvik",
+		"valerenga+",
+		"+valerenga",
+		"valerenga-vs-",
+		"valerenga+valerenga",
+		"-home",
+	} {
+		if spec, err := ParseFeedSlug(slug); err == nil {
+			t.Errorf("ParseFeedSlug(%q) = %+v, expected error", slug, spec)
+		}
+	}
+}
+
+func TestParseFeedSpecs(t *testing.T) {
+	specs, err := ParseFeedSpecs("valerenga-vs-storhamar, valerenga+storhamar")
+	if err != nil {
+		t.Fatalf("ParseFeedSpecs failed: %v", err)
+	}
+	if len(specs) != 2 || specs[0].Match != ical.MatchAll || specs[1].Match != ical.MatchAny {
+		t.Errorf("unexpected specs %+v", specs)
+	}
+
+	if _, err := ParseFeedSpecs("valerenga,,storhamar"); err == nil {
+		t.Error("expected error for empty feed")
+	}
+}
+
+func TestFeedSpec_Filter(t *testing.T) {
+	teams := TeamsBySlug([]ehl.Team{{ShortName: "Vålerenga"}, {ShortName: "Storhamar"}})
+
+	spec := FeedSpec{Slugs: []string{"valerenga", "storhamar"}, Match: ical.MatchAll, Side: ical.AwayGames}
+	filter, err := spec.Filter(teams)
+	if err != nil {
+		t.Fatalf("Filter failed: %v", err)
+	}
+	if !slices.Equal(filter.Teams, []string{"Vålerenga", "Storhamar"}) || filter.Match != ical.MatchAll || filter.Side != ical.AwayGames {
+		t.Errorf("unexpected filter %+v", filter)
+	}
+
+	if _, err := TeamSpec("narvik", ical.AllGames).Filter(teams); err == nil {
+		t.Error("expected error for unknown team")
+	}
+}
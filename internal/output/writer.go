@@ -4,7 +4,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/thomasoddsund/hockeykalender/internal/atom"
+	"github.com/thomasoddsund/hockeykalender/internal/diff"
 	"github.com/thomasoddsund/hockeykalender/internal/ehl"
 	"github.com/thomasoddsund/hockeykalender/internal/ical"
 )
@@ -24,14 +28,46 @@ func Filename(slug string, alarms []ical.Alarm) string {
 	return fmt.Sprintf("%s-%s.ics", slug, suffix)
 }
 
+// ParseFilename is the inverse of Filename: it splits a calendar filename
+// into its slug and alarms, e.g. "frisk-asker-1d-1h.ics" → "frisk-asker", [1d, 1h]
+func ParseFilename(name string) (string, []ical.Alarm, error) {
+	base, ok := strings.CutSuffix(name, ".ics")
+	if !ok || base == "" || strings.Contains(base, "/") {
+		return "", nil, fmt.Errorf("invalid calendar filename %q", name)
+	}
+
+	// Alarm suffixes are the trailing parts that parse as alarms
+	parts := strings.Split(base, "-")
+	end := len(parts)
+	for end > 1 {
+		if _, err := ical.ParseAlarm(parts[end-1]); err != nil {
+			break
+		}
+		end--
+	}
+	if len(parts)-end > ical.MaxAlarmSet {
+		return "", nil, fmt.Errorf("invalid calendar filename %q: more than %d alarms", name, ical.MaxAlarmSet)
+	}
+
+	var alarms []ical.Alarm
+	for _, part := range parts[end:] {
+		alarm, _ := ical.ParseAlarm(part)
+		alarms = append(alarms, alarm)
+	}
+
+	return strings.Join(parts[:end], "-"), alarms, nil
+}
+
 // WriteCalendar writes a calendar string to a file
 func WriteCalendar(dir, filename, content string) error {
 	path := filepath.Join(dir, filename)
 	return os.WriteFile(path, []byte(content), 0644)
 }
 
-// GenerateAllCalendars generates all calendar files (teams + EHL, all alarm combos)
-func GenerateAllCalendars(dir string, games []ehl.Game, teams []ehl.Team, seasonName string) (Stats, error) {
+// GenerateAllCalendars generates all calendar files (teams with their home and away variants +
+// combined series feed, one per alarm preset)
+// from the games of one or more seasons. opts are passed on to ical.GenerateCalendar.
+func GenerateAllCalendars(dir string, series ehl.Series, seasons []ehl.SeasonGames, teams []ehl.Team, presets [][]ical.Alarm, opts ...ical.Option) (Stats, error) {
 	var stats Stats
 
 	// Ensure output directory exists
@@ -39,38 +75,173 @@ func GenerateAllCalendars(dir string, games []ehl.Game, teams []ehl.Team, season
 		return stats, fmt.Errorf("failed to create output directory: %w", err)
 	}
 
-	// Get all alarm combinations
-	alarmCombos := ical.GenerateAlarmCombinations()
-
 	// Generate files for each team
 	for _, team := range teams {
-		slug := team.Slug()
+		for _, side := range ical.Sides {
+			filter := ical.TeamFilter(team.ShortName)
+			filter.Side = side
+			if err := writeFeed(dir, TeamSpec(team.Slug(), side).Slug(), filter, series, seasons, presets, opts, &stats); err != nil {
+				return stats, err
+			}
+		}
+	}
+
+	// Generate files for all games in the series
+	if err := writeFeed(dir, series.Slug, ical.Filter{}, series, seasons, presets, opts, &stats); err != nil {
+		return stats, err
+	}
+
+	return stats, nil
+}
+
+// GenerateCustomCalendars generates the calendar files of feed specs such as
+// multi-team and head-to-head feeds, one per alarm preset
+func GenerateCustomCalendars(dir string, series ehl.Series, seasons []ehl.SeasonGames, teams []ehl.Team, specs []FeedSpec, presets [][]ical.Alarm, opts ...ical.Option) (Stats, error) {
+	var stats Stats
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return stats, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	bySlug := TeamsBySlug(teams)
+	for _, spec := range specs {
+		filter, err := spec.Filter(bySlug)
+		if err != nil {
+			return stats, fmt.Errorf("feed %s: %w", spec.Slug(), err)
+		}
+		if err := writeFeed(dir, spec.Slug(), filter, series, seasons, presets, opts, &stats); err != nil {
+			return stats, err
+		}
+	}
+
+	return stats, nil
+}
+
+// VenueDir is the subdirectory of the per-venue calendars
+const VenueDir = "venues"
+
+// GenerateVenueCalendars generates a calendar of all games at each venue to {dir}/venues/{venue}.ics,
+// one per alarm preset
+func GenerateVenueCalendars(dir string, series ehl.Series, seasons []ehl.SeasonGames, venues []ehl.Venue, presets [][]ical.Alarm, opts ...ical.Option) (Stats, error) {
+	var stats Stats
+
+	dir = filepath.Join(dir, VenueDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return stats, fmt.Errorf("failed to create venue directory: %w", err)
+	}
+
+	for _, venue := range venues {
+		if err := writeFeed(dir, venue.Slug(), ical.VenueFilter(venue), series, seasons, presets, opts, &stats); err != nil {
+			return stats, err
+		}
+	}
+
+	return stats, nil
+}
+
+// GenerateAffectedCalendars regenerates the team, series, venue and custom feeds written by the
+// functions above that contain any of the affected games, see diff.Affected. Other feeds are left as they are.
+func GenerateAffectedCalendars(dir string, series ehl.Series, seasons []ehl.SeasonGames, teams []ehl.Team, venues []ehl.Venue, specs []FeedSpec, affected []ehl.Game, presets [][]ical.Alarm, opts ...ical.Option) (Stats, error) {
+	var stats Stats
 
-		for _, alarms := range alarmCombos {
-			content := ical.GenerateCalendar(games, team.ShortName, alarms, seasonName)
-			filename := Filename(slug, alarms)
+	if err := os.MkdirAll(filepath.Join(dir, VenueDir), 0755); err != nil {
+		return stats, fmt.Errorf("failed to create output directory: %w", err)
+	}
 
-			if err := WriteCalendar(dir, filename, content); err != nil {
-				return stats, fmt.Errorf("failed to write %s: %w", filename, err)
+	write := func(dir, slug string, filter ical.Filter) error {
+		for _, game := range affected {
+			if filter.Matches(game) {
+				return writeFeed(dir, slug, filter, series, seasons, presets, opts, &stats)
 			}
+		}
+		return nil
+	}
 
-			stats.FilesWritten++
-			stats.TotalBytes += int64(len(content))
+	for _, team := range teams {
+		for _, side := range ical.Sides {
+			filter := ical.TeamFilter(team.ShortName)
+			filter.Side = side
+			if err := write(dir, TeamSpec(team.Slug(), side).Slug(), filter); err != nil {
+				return stats, err
+			}
 		}
 	}
 
-	// Generate files for all EHL games
-	for _, alarms := range alarmCombos {
-		content := ical.GenerateCalendar(games, "", alarms, seasonName)
-		filename := Filename("ehl", alarms)
+	if err := write(dir, series.Slug, ical.Filter{}); err != nil {
+		return stats, err
+	}
+
+	for _, venue := range venues {
+		if err := write(filepath.Join(dir, VenueDir), venue.Slug(), ical.VenueFilter(venue)); err != nil {
+			return stats, err
+		}
+	}
+
+	bySlug := TeamsBySlug(teams)
+	for _, spec := range specs {
+		filter, err := spec.Filter(bySlug)
+		if err != nil {
+			return stats, fmt.Errorf("feed %s: %w", spec.Slug(), err)
+		}
+		if err := write(dir, spec.Slug(), filter); err != nil {
+			return stats, err
+		}
+	}
+
+	return stats, nil
+}
+
+// writeFeed writes one calendar file per alarm preset for a filter
+func writeFeed(dir, slug string, filter ical.Filter, series ehl.Series, seasons []ehl.SeasonGames, presets [][]ical.Alarm, opts []ical.Option, stats *Stats) error {
+	for _, alarms := range presets {
+		content := ical.GenerateCalendar(seasons, filter, alarms, series.Name, opts...)
+		filename := Filename(slug, alarms)
 
 		if err := WriteCalendar(dir, filename, content); err != nil {
-			return stats, fmt.Errorf("failed to write %s: %w", filename, err)
+			return fmt.Errorf("failed to write %s: %w", filename, err)
 		}
 
 		stats.FilesWritten++
 		stats.TotalBytes += int64(len(content))
 	}
+	return nil
+}
+
+// GenerateAllFeeds writes an Atom feed of schedule changes for each team ({team}.atom)
+// and for the whole series ({series}.atom). Times are shown in loc, and updated is the
+// update time of feeds without entries.
+func GenerateAllFeeds(dir string, series ehl.Series, teams []ehl.Team, events diff.History, loc *time.Location, updated time.Time) (Stats, error) {
+	var stats Stats
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return stats, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	write := func(slug, title string, events diff.History) error {
+		data, err := atom.Generate(series.Slug+"/"+slug, title+": endringer i terminlisten", series.Name, events, loc, updated)
+		if err != nil {
+			return err
+		}
+
+		filename := slug + ".atom"
+		if err := os.WriteFile(filepath.Join(dir, filename), data, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", filename, err)
+		}
+
+		stats.FilesWritten++
+		stats.TotalBytes += int64(len(data))
+		return nil
+	}
+
+	for _, team := range teams {
+		if err := write(team.Slug(), team.ShortName, atom.Involving(events, team.ShortName)); err != nil {
+			return stats, err
+		}
+	}
+
+	if err := write(series.Slug, series.Name, events); err != nil {
+		return stats, err
+	}
 
 	return stats, nil
 }
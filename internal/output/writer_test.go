@@ -3,8 +3,11 @@ package output
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/thomasoddsund/hockeykalender/internal/diff"
 	"github.com/thomasoddsund/hockeykalender/internal/ehl"
 	"github.com/thomasoddsund/hockeykalender/internal/ical"
 )
@@ -59,6 +62,10 @@ func TestWriteCalendar(t *testing.T) {
 	}
 }
 
+func testSeasons(games []ehl.Game) []ehl.SeasonGames {
+	return []ehl.SeasonGames{{Season: ehl.Season{Name: "2025/2026"}, Games: games}}
+}
+
 func TestGenerateAllCalendars(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -72,17 +79,17 @@ func TestGenerateAllCalendars(t *testing.T) {
 			UUID:     "game-1",
 			HomeTeam: teams[0],
 			AwayTeam: teams[1],
-			Venue:    "Test Arena",
+			Venue:    ehl.Venue{Name: "Test Arena"},
 		},
 	}
 
-	stats, err := GenerateAllCalendars(tmpDir, games, teams, "2025/2026")
+	stats, err := GenerateAllCalendars(tmpDir, ehl.DefaultSeries, testSeasons(games), teams, ical.DefaultAlarmPresets())
 	if err != nil {
 		t.Fatalf("GenerateAllCalendars failed: %v", err)
 	}
 
-	// 3 entities (2 teams + EHL) x 16 alarm combos = 48 files
-	expectedFiles := 3 * 16
+	// (2 teams x all/home/away + EHL) x 16 alarm combos = 112 files
+	expectedFiles := (2*3 + 1) * 16
 	if stats.FilesWritten != expectedFiles {
 		t.Errorf("expected %d files, got %d", expectedFiles, stats.FilesWritten)
 	}
@@ -91,6 +98,8 @@ func TestGenerateAllCalendars(t *testing.T) {
 	checkFiles := []string{
 		"valerenga.ics",
 		"valerenga-1d.ics",
+		"valerenga-home.ics",
+		"valerenga-away-1d-1h.ics",
 		"storhamar.ics",
 		"ehl.ics",
 		"ehl-1d-3h-1h-15m.ics",
@@ -103,3 +112,278 @@ func TestGenerateAllCalendars(t *testing.T) {
 		}
 	}
 }
+
+func TestGenerateAllCalendars_Series(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	teams := []ehl.Team{
+		{ShortName: "Vålerenga"},
+		{ShortName: "Storhamar"},
+	}
+
+	games := []ehl.Game{
+		{
+			UUID:     "game-1",
+			HomeTeam: teams[0],
+			AwayTeam: teams[1],
+			Venue:    ehl.Venue{Name: "Test Arena"},
+		},
+	}
+
+	series := ehl.Series{Slug: "kvinner", UUID: "series-women", Name: "Kvinneligaen"}
+
+	_, err := GenerateAllCalendars(tmpDir, series, testSeasons(games), teams, ical.DefaultAlarmPresets())
+	if err != nil {
+		t.Fatalf("GenerateAllCalendars failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "kvinner.ics"))
+	if err != nil {
+		t.Fatalf("expected combined series feed: %v", err)
+	}
+	if !strings.Contains(string(data), "X-WR-CALNAME:Kvinneligaen 2025/2026") {
+		t.Error("expected calendar name to include series name")
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, "ehl.ics")); !os.IsNotExist(err) {
+		t.Error("expected no ehl.ics for another series")
+	}
+}
+
+func TestParseFilename(t *testing.T) {
+	tests := []struct {
+		name       string
+		filename   string
+		wantSlug   string
+		wantAlarms []ical.Alarm
+		wantErr    bool
+	}{
+		{"no alarms", "valerenga.ics", "valerenga", nil, false},
+		{"hyphenated slug", "frisk-asker.ics", "frisk-asker", nil, false},
+		{"hyphenated slug with alarms", "frisk-asker-1d-1h.ics", "frisk-asker", []ical.Alarm{ical.Alarm1Day, ical.Alarm1Hour}, false},
+		{"all alarms", "ehl-1d-3h-1h-15m.ics", "ehl", []ical.Alarm{ical.Alarm1Day, ical.Alarm3Hours, ical.Alarm1Hour, ical.Alarm15Min}, false},
+		{"custom alarms", "valerenga-2h-30m.ics", "valerenga", []ical.Alarm{ical.Alarm(2 * time.Hour), ical.Alarm(30 * time.Minute)}, false},
+		{"too many alarms", "ehl-2d-1d-3h-1h-15m.ics", "", nil, true},
+		{"wrong extension", "valerenga.txt", "", nil, true},
+		{"path", "ehl/valerenga.ics", "", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slug, alarms, err := ParseFilename(tt.filename)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFilename() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if slug != tt.wantSlug {
+				t.Errorf("ParseFilename() slug = %s, want %s", slug, tt.wantSlug)
+			}
+			if ical.AlarmSetSuffix(alarms) != ical.AlarmSetSuffix(tt.wantAlarms) {
+				t.Errorf("ParseFilename() alarms = %v, want %v", alarms, tt.wantAlarms)
+			}
+			if !tt.wantErr && Filename(slug, alarms) != tt.filename {
+				t.Errorf("Filename(ParseFilename()) = %s, want %s", Filename(slug, alarms), tt.filename)
+			}
+		})
+	}
+}
+
+func TestGenerateAllCalendars_Presets(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	teams := []ehl.Team{{ShortName: "Vålerenga"}}
+	games := []ehl.Game{{UUID: "game-1", HomeTeam: teams[0], AwayTeam: ehl.Team{ShortName: "Storhamar"}}}
+
+	presets, err := ical.ParseAlarmPresets("none,2h,1d-30m")
+	if err != nil {
+		t.Fatalf("ParseAlarmPresets failed: %v", err)
+	}
+
+	stats, err := GenerateAllCalendars(tmpDir, ehl.DefaultSeries, testSeasons(games), teams, presets)
+	if err != nil {
+		t.Fatalf("GenerateAllCalendars failed: %v", err)
+	}
+
+	// (1 team x all/home/away + EHL) x 3 presets
+	if stats.FilesWritten != 12 {
+		t.Errorf("expected 12 files, got %d", stats.FilesWritten)
+	}
+
+	for _, f := range []string{"valerenga.ics", "valerenga-2h.ics", "ehl-1d-30m.ics"} {
+		if _, err := os.Stat(filepath.Join(tmpDir, f)); os.IsNotExist(err) {
+			t.Errorf("expected file %s to exist", f)
+		}
+	}
+}
+
+func TestGenerateAllFeeds(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	teams := []ehl.Team{{ShortName: "Vålerenga"}, {ShortName: "Storhamar"}, {ShortName: "Narvik"}}
+	game := ehl.Game{
+		UUID:      "game-1",
+		StartTime: time.Date(2025, 9, 11, 17, 0, 0, 0, time.UTC),
+		HomeTeam:  teams[0],
+		AwayTeam:  teams[1],
+		Venue:     ehl.Venue{Name: "Jordal Amfi"},
+	}
+	now := time.Date(2025, 9, 1, 6, 0, 0, 0, time.UTC)
+	events := diff.History{}.Add(diff.Compare(nil, []ehl.Game{game}), now, now)
+
+	stats, err := GenerateAllFeeds(tmpDir, ehl.DefaultSeries, teams, events, time.UTC, now)
+	if err != nil {
+		t.Fatalf("GenerateAllFeeds failed: %v", err)
+	}
+	if stats.FilesWritten != 4 {
+		t.Errorf("expected 4 files, got %d", stats.FilesWritten)
+	}
+
+	tests := []struct {
+		file     string
+		hasEntry bool
+	}{
+		{"valerenga.atom", true},
+		{"storhamar.atom", true},
+		{"narvik.atom", false},
+		{"ehl.atom", true},
+	}
+
+	for _, tt := range tests {
+		data, err := os.ReadFile(filepath.Join(tmpDir, tt.file))
+		if err != nil {
+			t.Errorf("expected file %s to exist", tt.file)
+			continue
+		}
+		if got := strings.Contains(string(data), "game/game-1/added"); got != tt.hasEntry {
+			t.Errorf("%s: expected entry %v, got %v", tt.file, tt.hasEntry, got)
+		}
+	}
+}
+
+func TestGenerateCustomCalendars(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	teams := []ehl.Team{{ShortName: "Vålerenga"}, {ShortName: "Storhamar"}, {ShortName: "Narvik"}}
+	games := []ehl.Game{
+		{UUID: "game-1", HomeTeam: teams[0], AwayTeam: teams[1]},
+		{UUID: "game-2", HomeTeam: teams[2], AwayTeam: teams[0]},
+	}
+
+	specs, err := ParseFeedSpecs("valerenga-vs-storhamar,storhamar+narvik")
+	if err != nil {
+		t.Fatalf("ParseFeedSpecs failed: %v", err)
+	}
+
+	stats, err := GenerateCustomCalendars(tmpDir, ehl.DefaultSeries, testSeasons(games), teams, specs, [][]ical.Alarm{nil, {ical.Alarm1Hour}})
+	if err != nil {
+		t.Fatalf("GenerateCustomCalendars failed: %v", err)
+	}
+	if stats.FilesWritten != 4 {
+		t.Errorf("expected 4 files, got %d", stats.FilesWritten)
+	}
+
+	tests := []struct {
+		file   string
+		events int
+	}{
+		{"valerenga-vs-storhamar.ics", 1},
+		{"valerenga-vs-storhamar-1h.ics", 1},
+		{"storhamar+narvik.ics", 2},
+	}
+
+	for _, tt := range tests {
+		data, err := os.ReadFile(filepath.Join(tmpDir, tt.file))
+		if err != nil {
+			t.Errorf("expected file %s to exist", tt.file)
+			continue
+		}
+		if count := strings.Count(string(data), "BEGIN:VEVENT"); count != tt.events {
+			t.Errorf("%s: expected %d events, got %d", tt.file, tt.events, count)
+		}
+	}
+
+	unknown, _ := ParseFeedSpecs("valerenga-vs-lillehammer")
+	if _, err := GenerateCustomCalendars(tmpDir, ehl.DefaultSeries, testSeasons(games), teams, unknown, ical.DefaultAlarmPresets()); err == nil {
+		t.Error("expected error for unknown team")
+	}
+}
+
+func TestGenerateVenueCalendars(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	jordal := ehl.Venue{UUID: "venue-jordal", Name: "Jordal Amfi"}
+	cc := ehl.Venue{UUID: "venue-cc", Name: "CC Amfi, Hamar"}
+	games := []ehl.Game{
+		{UUID: "game-1", HomeTeam: ehl.Team{ShortName: "Vålerenga"}, AwayTeam: ehl.Team{ShortName: "Storhamar"}, Venue: jordal},
+		{UUID: "game-2", HomeTeam: ehl.Team{ShortName: "Storhamar"}, AwayTeam: ehl.Team{ShortName: "Vålerenga"}, Venue: cc},
+		// Neutral-site game
+		{UUID: "game-3", HomeTeam: ehl.Team{ShortName: "Narvik"}, AwayTeam: ehl.Team{ShortName: "Sparta"}, Venue: jordal},
+	}
+
+	stats, err := GenerateVenueCalendars(tmpDir, ehl.DefaultSeries, testSeasons(games), ehl.ExtractVenues(games), [][]ical.Alarm{nil, {ical.Alarm1Hour}})
+	if err != nil {
+		t.Fatalf("GenerateVenueCalendars failed: %v", err)
+	}
+	if stats.FilesWritten != 4 {
+		t.Errorf("expected 4 files, got %d", stats.FilesWritten)
+	}
+
+	tests := []struct {
+		file    string
+		events  int
+		calName string
+	}{
+		{"venues/jordal-amfi.ics", 2, "X-WR-CALNAME:Jordal Amfi - EHL 2025/2026"},
+		{"venues/jordal-amfi-1h.ics", 2, "X-WR-CALNAME:Jordal Amfi - EHL 2025/2026"},
+		{"venues/cc-amfi-hamar.ics", 1, `X-WR-CALNAME:CC Amfi\, Hamar - EHL 2025/2026`},
+	}
+
+	for _, tt := range tests {
+		data, err := os.ReadFile(filepath.Join(tmpDir, tt.file))
+		if err != nil {
+			t.Errorf("expected file %s to exist", tt.file)
+			continue
+		}
+		if count := strings.Count(string(data), "BEGIN:VEVENT"); count != tt.events {
+			t.Errorf("%s: expected %d events, got %d", tt.file, tt.events, count)
+		}
+		if !strings.Contains(string(data), tt.calName) {
+			t.Errorf("%s: expected %s", tt.file, tt.calName)
+		}
+	}
+}
+
+func TestGenerateAffectedCalendars(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	jordal := ehl.Venue{UUID: "venue-jordal", Name: "Jordal Amfi"}
+	cc := ehl.Venue{UUID: "venue-cc", Name: "CC Amfi, Hamar"}
+	live := ehl.Game{UUID: "game-1", State: ehl.StateLive, HomeTeam: ehl.Team{UUID: "vif", ShortName: "Vålerenga"}, AwayTeam: ehl.Team{UUID: "sth", ShortName: "Storhamar"}, Venue: jordal}
+	games := []ehl.Game{
+		live,
+		{UUID: "game-2", HomeTeam: ehl.Team{UUID: "nar", ShortName: "Narvik"}, AwayTeam: ehl.Team{UUID: "spa", ShortName: "Sparta"}, Venue: cc},
+	}
+	teams := ehl.ExtractTeams(games)
+	specs := []FeedSpec{{Slugs: []string{"narvik", "sparta"}, Match: ical.MatchAll}}
+
+	stats, err := GenerateAffectedCalendars(tmpDir, ehl.DefaultSeries, testSeasons(games), teams, ehl.ExtractVenues(games), specs, []ehl.Game{live}, [][]ical.Alarm{nil})
+	if err != nil {
+		t.Fatalf("GenerateAffectedCalendars failed: %v", err)
+	}
+
+	written := []string{"valerenga.ics", "valerenga-home.ics", "storhamar.ics", "storhamar-away.ics", "ehl.ics", "venues/jordal-amfi.ics"}
+	untouched := []string{"valerenga-away.ics", "storhamar-home.ics", "narvik.ics", "sparta.ics", "narvik-vs-sparta.ics", "venues/cc-amfi-hamar.ics"}
+
+	if stats.FilesWritten != len(written) {
+		t.Errorf("expected %d files, got %d", len(written), stats.FilesWritten)
+	}
+	for _, file := range written {
+		if _, err := os.Stat(filepath.Join(tmpDir, file)); err != nil {
+			t.Errorf("expected %s to be written", file)
+		}
+	}
+	for _, file := range untouched {
+		if _, err := os.Stat(filepath.Join(tmpDir, file)); err == nil {
+			t.Errorf("expected %s to be left alone", file)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thomasoddsund/hockeykalender/internal/ehl"
+	"github.com/thomasoddsund/hockeykalender/internal/ical"
+	"github.com/thomasoddsund/hockeykalender/internal/output"
+	"github.com/thomasoddsund/hockeykalender/internal/state"
+)
+
+// snapshot is an immutable view of the latest fetched games
+type snapshot struct {
+	seasons      []ehl.SeasonGames
+	teams        map[string]ehl.Team  // by slug
+	venues       map[string]ehl.Venue // by slug
+	revisions    map[string]ical.Revision
+	version      string    // hash of the game data, part of every ETag
+	lastModified time.Time // when the game data last changed
+}
+
+// Server renders calendar feeds on demand from the latest games in memory.
+// Feeds use the same URL scheme as the static files, see output.Filename.
+type Server struct {
+	client  *ehl.Client
+	series  ehl.Series
+	overlap time.Duration
+	grace   time.Duration
+	opts    []ical.Option
+	now     func() time.Time
+
+	mu        sync.RWMutex
+	snap      *snapshot
+	state     *state.State // Only touched by update, under mu
+	statePath string       // Where the state is saved after each refresh, empty to keep it in memory
+}
+
+// New creates a server for a series. overlap is how far back the previous
+// season's games are kept in the feeds, 0 to only serve the current season.
+// Cancelled and removed games are served as cancelled events for grace.
+// opts are passed on to ical.GenerateCalendar.
+func New(client *ehl.Client, series ehl.Series, overlap, grace time.Duration, opts ...ical.Option) *Server {
+	return &Server{
+		client:  client,
+		series:  series,
+		overlap: overlap,
+		grace:   grace,
+		opts:    opts,
+		now:     time.Now,
+		state:   state.New(),
+	}
+}
+
+// LoadState loads the state file at path, the same file cmd/generate keeps, and saves the
+// state there after every refresh, so that revisions survive restarts. Call it before Refresh.
+func (s *Server) LoadState(path string) error {
+	st, err := state.Load(path)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = st
+	s.statePath = path
+	return nil
+}
+
+// Refresh fetches the current games. On error the previous games are kept.
+func (s *Server) Refresh(ctx context.Context) error {
+	now := s.now()
+
+	season, games, err := s.client.CurrentSeason(ctx, now)
+	if err != nil {
+		return fmt.Errorf("failed to fetch current season: %w", err)
+	}
+	seasons := []ehl.SeasonGames{{Season: season, Games: games}}
+
+	if s.overlap > 0 {
+		previous, ok, err := s.client.PreviousSeason(ctx, season)
+		if err != nil {
+			return fmt.Errorf("failed to fetch previous season: %w", err)
+		}
+		if ok {
+			previousGames, err := s.client.FetchGames(ctx, previous.UUID)
+			if err != nil {
+				return fmt.Errorf("failed to fetch previous season: %w", err)
+			}
+			seasons = append([]ehl.SeasonGames{{Season: previous, Games: previousGames}}, seasons...)
+		}
+	}
+
+	return s.update(seasons, now)
+}
+
+// update swaps in new games, keeping Last-Modified unless the data changed.
+// seasons holds the complete game lists, oldest first; all but the last
+// are trimmed to the overlap window. On error nothing changes.
+func (s *Server) update(seasons []ehl.SeasonGames, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := s.state.Clone()
+	var published []ehl.SeasonGames
+	for i, sg := range seasons {
+		games, err := next.Update(sg.Season.UUID, sg.Games, now, s.grace)
+		if err != nil {
+			log.Printf("Warning: %s: %v, not removing the missing games", sg.Season.Name, err)
+		}
+		if i < len(seasons)-1 {
+			games = ehl.GamesSince(games, now.Add(-s.overlap))
+		}
+		if len(games) > 0 || i == len(seasons)-1 {
+			published = append(published, ehl.SeasonGames{Season: sg.Season, Games: games})
+		}
+	}
+
+	data, _ := json.Marshal(published)
+	sum := sha256.Sum256(data)
+	version := hex.EncodeToString(sum[:8])
+
+	changed := s.snap == nil || s.snap.version != version
+	if changed {
+		next.UpdateDescriptions(ical.Descriptions(published, s.opts...), now)
+	}
+	if s.statePath != "" {
+		if err := next.Save(s.statePath); err != nil {
+			return fmt.Errorf("failed to save state: %w", err)
+		}
+	}
+
+	s.state = next
+	if !changed {
+		return nil
+	}
+
+	games := ehl.MergeSeasonGames(published)
+	venues := make(map[string]ehl.Venue)
+	for _, venue := range ehl.ExtractVenues(games) {
+		venues[venue.Slug()] = venue
+	}
+
+	s.snap = &snapshot{
+		seasons:      published,
+		teams:        output.TeamsBySlug(ehl.ExtractTeams(games)),
+		venues:       venues,
+		revisions:    s.state.Revisions(),
+		version:      version,
+		lastModified: now.UTC().Truncate(time.Second),
+	}
+	return nil
+}
+
+// Run refreshes the games every interval until ctx is cancelled
+func (s *Server) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Refresh(ctx); err != nil {
+				log.Printf("Refresh failed, serving previous data: %v", err)
+			}
+		}
+	}
+}
+
+// ServeHTTP renders the requested feed, answering conditional requests with 304
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	name, isVenue := strings.CutPrefix(path, output.VenueDir+"/")
+
+	slug, alarms, err := output.ParseFilename(name)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mu.RLock()
+	snap := s.snap
+	s.mu.RUnlock()
+
+	if snap == nil {
+		http.Error(w, "calendar data not loaded yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	filter, ok := s.filter(snap, slug, isVenue)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	opts := append(s.opts[:len(s.opts):len(s.opts)], ical.WithRevisions(snap.revisions))
+	content := ical.GenerateCalendar(snap.seasons, filter, alarms, s.series.Name, opts...)
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("ETag", etag(snap.version, path))
+	http.ServeContent(w, r, name, snap.lastModified, bytes.NewReader([]byte(content)))
+}
+
+// filter resolves the slug of a feed to its filter, and false if there is no such feed
+func (s *Server) filter(snap *snapshot, slug string, isVenue bool) (ical.Filter, bool) {
+	if isVenue {
+		venue, ok := snap.venues[slug]
+		return ical.VenueFilter(venue), ok
+	}
+
+	spec, err := output.ParseFeedSlug(slug)
+	if err != nil {
+		return ical.Filter{}, false
+	}
+
+	if spec.Slug() == s.series.Slug {
+		return ical.Filter{}, true
+	}
+
+	filter, err := spec.Filter(snap.teams)
+	return filter, err == nil
+}
+
+// etag identifies a feed rendered from a given version of the game data
+func etag(version, name string) string {
+	sum := sha256.Sum256([]byte(version + "/" + name))
+	return `"` + hex.EncodeToString(sum[:8]) + `"`
+}
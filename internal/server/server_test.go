@@ -0,0 +1,321 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/thomasoddsund/hockeykalender/internal/ehl"
+)
+
+const testSeasonsResponse = `{
+	"season": [
+		{"uuid": "bir2zwf4qa", "names": [{"language": "no", "translation": "2025/2026"}]}
+	],
+	"gameType": [
+		{"uuid": "qQ9-af37Ti40B", "names": [{"language": "no", "translation": "Serie"}]}
+	]
+}`
+
+const testGamesResponse = `{
+	"gameInfo": [
+		{
+			"uuid": "aqdidacsop",
+			"rawStartDateTime": "2025-09-11T17:00:00.000Z",
+			"state": "pre-game",
+			"homeTeamInfo": {"uuid": "qQ0-A0eF1CWG5", "code": "VIF", "names": {"full": "Vålerenga Ishockey Elite", "short": "Vålerenga"}, "score": 0},
+			"awayTeamInfo": {"uuid": "qQ0-8E8X1CsEP", "code": "STH", "names": {"full": "Storhamar Ishockey Elite", "short": "Storhamar"}, "score": 0},
+			"venueInfo": {"uuid": "venue-123", "name": "Jordal Amfi"}
+		},
+		{
+			"uuid": "bqdidacsop",
+			"rawStartDateTime": "2025-09-12T18:00:00.000Z",
+			"state": "pre-game",
+			"homeTeamInfo": {"uuid": "qQ0-FRI", "code": "FRI", "names": {"full": "Frisk Asker Ishockey", "short": "Frisk Asker"}, "score": 0},
+			"awayTeamInfo": {"uuid": "qQ0-8E8X1CsEP", "code": "STH", "names": {"full": "Storhamar Ishockey Elite", "short": "Storhamar"}, "score": 0},
+			"venueInfo": {"uuid": "venue-456", "name": "Askerhallen"}
+		}
+	]
+}`
+
+// newTestAPI stands in for the EHL API. The venue of the first game can be changed through venue.
+func newTestAPI(t *testing.T, venue *atomic.Value) *httptest.Server {
+	t.Helper()
+	venue.Store("Jordal Amfi")
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/sports-v2/season-series-game-types-filter":
+			w.Write([]byte(testSeasonsResponse))
+		case "/api/sports-v2/game-schedule":
+			w.Write([]byte(strings.Replace(testGamesResponse, "Jordal Amfi", venue.Load().(string), 1)))
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+}
+
+func newTestServer(t *testing.T, api *httptest.Server) *Server {
+	t.Helper()
+	srv := New(ehl.NewClient(api.URL), ehl.DefaultSeries, 0, 14*24*time.Hour)
+	srv.now = func() time.Time { return time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC) }
+	if err := srv.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	return srv
+}
+
+func get(srv http.Handler, path string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServeTeamCalendar(t *testing.T) {
+	var venue atomic.Value
+	api := newTestAPI(t, &venue)
+	defer api.Close()
+	srv := newTestServer(t, api)
+
+	rec := get(srv, "/frisk-asker-1d-1h.ics", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	body := rec.Body.String()
+	if count := strings.Count(body, "BEGIN:VEVENT"); count != 1 {
+		t.Errorf("expected 1 event for Frisk Asker, got %d", count)
+	}
+	if count := strings.Count(body, "BEGIN:VALARM"); count != 2 {
+		t.Errorf("expected 2 alarms, got %d", count)
+	}
+	if !strings.Contains(body, "X-WR-CALNAME:Frisk Asker - EHL 2025/2026") {
+		t.Error("expected team calendar name")
+	}
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/calendar") {
+		t.Errorf("unexpected Content-Type %q", rec.Header().Get("Content-Type"))
+	}
+	if rec.Header().Get("ETag") == "" || rec.Header().Get("Last-Modified") == "" {
+		t.Error("expected ETag and Last-Modified headers")
+	}
+}
+
+func TestServeFilteredCalendar(t *testing.T) {
+	var venue atomic.Value
+	api := newTestAPI(t, &venue)
+	defer api.Close()
+	srv := newTestServer(t, api)
+
+	tests := []struct {
+		path   string
+		events int
+	}{
+		{"/storhamar-home.ics", 0},
+		{"/storhamar-away-1h.ics", 2},
+		{"/frisk-asker-home.ics", 1},
+		{"/valerenga+frisk-asker.ics", 2},
+		{"/storhamar-vs-valerenga-1d.ics", 1},
+		{"/storhamar-vs-valerenga-home.ics", 0},
+	}
+
+	for _, tt := range tests {
+		rec := get(srv, tt.path, nil)
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: expected 200, got %d", tt.path, rec.Code)
+			continue
+		}
+		if count := strings.Count(rec.Body.String(), "BEGIN:VEVENT"); count != tt.events {
+			t.Errorf("%s: expected %d events, got %d", tt.path, tt.events, count)
+		}
+	}
+}
+
+func TestServeVenueCalendar(t *testing.T) {
+	var venue atomic.Value
+	api := newTestAPI(t, &venue)
+	defer api.Close()
+	srv := newTestServer(t, api)
+
+	rec := get(srv, "/venues/jordal-amfi-1h.ics", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+	if count := strings.Count(body, "BEGIN:VEVENT"); count != 1 {
+		t.Errorf("expected 1 event, got %d", count)
+	}
+	if !strings.Contains(body, "X-WR-CALNAME:Jordal Amfi - EHL 2025/2026") {
+		t.Error("expected venue calendar name")
+	}
+
+	for _, path := range []string{"/venues/valerenga.ics", "/venues/unknown.ics", "/jordal-amfi.ics"} {
+		if rec := get(srv, path, nil); rec.Code != http.StatusNotFound {
+			t.Errorf("%s: expected 404, got %d", path, rec.Code)
+		}
+	}
+}
+
+func TestServeSeriesCalendar(t *testing.T) {
+	var venue atomic.Value
+	api := newTestAPI(t, &venue)
+	defer api.Close()
+	srv := newTestServer(t, api)
+
+	rec := get(srv, "/ehl.ics", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if count := strings.Count(rec.Body.String(), "BEGIN:VEVENT"); count != 2 {
+		t.Errorf("expected 2 events, got %d", count)
+	}
+}
+
+func TestServeNotFound(t *testing.T) {
+	var venue atomic.Value
+	api := newTestAPI(t, &venue)
+	defer api.Close()
+	srv := newTestServer(t, api)
+
+	for _, path := range []string{"/lillehammer.ics", "/valerenga.txt", "/sub/valerenga.ics", "/ehl-home.ics", "/lillehammer-away.ics", "/valerenga+lillehammer.ics", "/ehl-vs-valerenga.ics", "/valerenga+valerenga.ics"} {
+		if rec := get(srv, path, nil); rec.Code != http.StatusNotFound {
+			t.Errorf("%s: expected 404, got %d", path, rec.Code)
+		}
+	}
+}
+
+func TestServeNotModified(t *testing.T) {
+	var venue atomic.Value
+	api := newTestAPI(t, &venue)
+	defer api.Close()
+	srv := newTestServer(t, api)
+
+	first := get(srv, "/valerenga.ics", nil)
+	etag := first.Header().Get("ETag")
+
+	if rec := get(srv, "/valerenga.ics", map[string]string{"If-None-Match": etag}); rec.Code != http.StatusNotModified {
+		t.Errorf("If-None-Match: expected 304, got %d", rec.Code)
+	}
+
+	lastModified := first.Header().Get("Last-Modified")
+	if rec := get(srv, "/valerenga.ics", map[string]string{"If-Modified-Since": lastModified}); rec.Code != http.StatusNotModified {
+		t.Errorf("If-Modified-Since: expected 304, got %d", rec.Code)
+	}
+
+	// Other feeds have their own ETag
+	if other := get(srv, "/valerenga-1h.ics", nil); other.Header().Get("ETag") == etag {
+		t.Error("expected different ETag for a different feed")
+	}
+}
+
+func TestRefreshChangesETagOnlyWhenDataChanges(t *testing.T) {
+	var venue atomic.Value
+	api := newTestAPI(t, &venue)
+	defer api.Close()
+	srv := newTestServer(t, api)
+
+	before := get(srv, "/valerenga.ics", nil)
+
+	srv.now = func() time.Time { return time.Date(2025, 9, 2, 12, 0, 0, 0, time.UTC) }
+	if err := srv.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	same := get(srv, "/valerenga.ics", nil)
+	if same.Header().Get("ETag") != before.Header().Get("ETag") || same.Header().Get("Last-Modified") != before.Header().Get("Last-Modified") {
+		t.Error("expected unchanged ETag and Last-Modified for unchanged data")
+	}
+
+	venue.Store("Furuset Forum")
+	if err := srv.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	changed := get(srv, "/valerenga.ics", map[string]string{"If-None-Match": before.Header().Get("ETag")})
+	if changed.Code != http.StatusOK {
+		t.Fatalf("expected 200 after data change, got %d", changed.Code)
+	}
+	if !strings.Contains(changed.Body.String(), "LOCATION:Furuset Forum") {
+		t.Error("expected the new venue")
+	}
+}
+
+func TestServeBeforeRefresh(t *testing.T) {
+	srv := New(ehl.NewClient("http://127.0.0.1:0"), ehl.DefaultSeries, 0, 0)
+
+	if rec := get(srv, "/ehl.ics", nil); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503, got %d", rec.Code)
+	}
+}
+
+func TestServeStableContent(t *testing.T) {
+	var venue atomic.Value
+	api := newTestAPI(t, &venue)
+	defer api.Close()
+	srv := newTestServer(t, api)
+
+	first := get(srv, "/valerenga.ics", nil).Body.String()
+	if !strings.Contains(first, "DTSTAMP:20250901T120000Z") {
+		t.Error("expected DTSTAMP from the time the game was first seen")
+	}
+
+	srv.now = func() time.Time { return time.Date(2025, 9, 2, 12, 0, 0, 0, time.UTC) }
+	if err := srv.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	if second := get(srv, "/valerenga.ics", nil).Body.String(); second != first {
+		t.Error("expected identical content for unchanged games")
+	}
+}
+
+func TestRefreshLeavesMissingScheduleAlone(t *testing.T) {
+	var venue atomic.Value
+	api := newTestAPI(t, &venue)
+	defer api.Close()
+	srv := newTestServer(t, api)
+
+	season := srv.snap.seasons[0].Season
+	if err := srv.update([]ehl.SeasonGames{{Season: season}}, srv.now()); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if body := get(srv, "/ehl.ics", nil).Body.String(); strings.Contains(body, "STATUS:CANCELLED") {
+		t.Error("expected the missing games not to be cancelled")
+	}
+	for uuid, entry := range srv.state.Games {
+		if entry.Game.State == ehl.StateRemoved {
+			t.Errorf("expected %s not to be marked removed", uuid)
+		}
+	}
+}
+
+func TestServeStableContentAcrossRestarts(t *testing.T) {
+	var venue atomic.Value
+	api := newTestAPI(t, &venue)
+	defer api.Close()
+	path := filepath.Join(t.TempDir(), "ehl.json")
+
+	start := func(now time.Time) *Server {
+		srv := New(ehl.NewClient(api.URL), ehl.DefaultSeries, 0, 14*24*time.Hour)
+		srv.now = func() time.Time { return now }
+		if err := srv.LoadState(path); err != nil {
+			t.Fatalf("LoadState failed: %v", err)
+		}
+		if err := srv.Refresh(context.Background()); err != nil {
+			t.Fatalf("Refresh failed: %v", err)
+		}
+		return srv
+	}
+
+	first := get(start(time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)), "/valerenga.ics", nil).Body.String()
+	second := get(start(time.Date(2025, 9, 2, 12, 0, 0, 0, time.UTC)), "/valerenga.ics", nil).Body.String()
+	if second != first {
+		t.Error("expected identical content after a restart with the same state file")
+	}
+}
@@ -0,0 +1,86 @@
+package standings
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+)
+
+// pageTemplate is a self-contained page, so it works in every output directory
+var pageTemplate = template.Must(template.New("standings").Funcs(template.FuncMap{
+	"signed": func(n int) string {
+		if n > 0 {
+			return fmt.Sprintf("+%d", n)
+		}
+		return fmt.Sprint(n)
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="no">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{.Title}}</title>
+    <style>
+        body { font-family: -apple-system, BlinkMacSystemFont, sans-serif; background: #060a10; color: #e8eef4; margin: 0; padding: 2rem 1rem; }
+        main { max-width: 48rem; margin: 0 auto; }
+        h1 { font-weight: 600; margin-bottom: 1.5rem; }
+        table { width: 100%; border-collapse: collapse; font-variant-numeric: tabular-nums; }
+        th, td { padding: 0.5rem; text-align: right; border-bottom: 1px solid rgba(255, 255, 255, 0.08); }
+        th { color: #7a8ca0; font-weight: 500; }
+        th.team, td.team { text-align: left; }
+        td.points { font-weight: 600; color: #00d4ff; }
+        footer { color: #7a8ca0; font-size: 0.875rem; margin-top: 1.5rem; }
+    </style>
+</head>
+<body>
+    <main>
+        <h1>{{.Title}}</h1>
+        <table>
+            <thead>
+                <tr>
+                    <th>#</th>
+                    <th class="team">Lag</th>
+                    <th><abbr title="Kamper">K</abbr></th>
+                    <th><abbr title="Seier">S</abbr></th>
+                    <th><abbr title="Seier etter overtid eller straffer">SOT</abbr></th>
+                    <th><abbr title="Tap etter overtid eller straffer">TOT</abbr></th>
+                    <th><abbr title="Tap">T</abbr></th>
+                    <th>Mål</th>
+                    <th>+/-</th>
+                    <th><abbr title="Poeng">P</abbr></th>
+                </tr>
+            </thead>
+            <tbody>
+{{- range .Table}}
+                <tr>
+                    <td>{{.Position}}</td>
+                    <td class="team">{{.Team.ShortName}}</td>
+                    <td>{{.Played}}</td>
+                    <td>{{.RegulationWins}}</td>
+                    <td>{{.OvertimeWins}}</td>
+                    <td>{{.OvertimeLosses}}</td>
+                    <td>{{.RegulationLosses}}</td>
+                    <td>{{.GoalsFor}}-{{.GoalsAgainst}}</td>
+                    <td>{{signed .GoalDifference}}</td>
+                    <td class="points">{{.Points}}</td>
+                </tr>
+{{- end}}
+            </tbody>
+        </table>
+        <footer>
+            <p>Oppdatert {{.Updated}}. Seier gir 3 poeng, seier etter overtid eller straffer 2 poeng og tap etter overtid eller straffer 1 poeng.</p>
+        </footer>
+    </main>
+</body>
+</html>
+`))
+
+// WriteHTML writes the table as an HTML page. updated is shown in loc.
+func WriteHTML(w io.Writer, title string, table Table, updated time.Time, loc *time.Location) error {
+	return pageTemplate.Execute(w, struct {
+		Title   string
+		Table   Table
+		Updated string
+	}{title, table, updated.In(loc).Format("02.01.2006 15:04")})
+}
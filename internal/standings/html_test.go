@@ -0,0 +1,42 @@
+package standings
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thomasoddsund/hockeykalender/internal/ehl"
+)
+
+func TestWriteHTML(t *testing.T) {
+	oslo, _ := time.LoadLocation("Europe/Oslo")
+	table := Compute([]ehl.Game{
+		result("Vålerenga", "Storhamar", 4, 1, ehl.DecisionRegulation),
+		result("Narvik", "<script>", 2, 3, ehl.DecisionShootout),
+	})
+
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, "Tabell - EHL 2025/2026", table, kickoff, oslo); err != nil {
+		t.Fatalf("WriteHTML failed: %v", err)
+	}
+	page := buf.String()
+
+	expected := []string{
+		"<title>Tabell - EHL 2025/2026</title>",
+		"<td class=\"team\">Vålerenga</td>",
+		"<td>4-1</td>",
+		"<td>&#43;3</td>", // html/template escapes +
+		"<td>-3</td>",
+		"<td class=\"team\">&lt;script&gt;</td>",
+		"Oppdatert 11.09.2025 19:00",
+	}
+	for _, s := range expected {
+		if !strings.Contains(page, s) {
+			t.Errorf("expected %q in page", s)
+		}
+	}
+	if strings.Count(page, "<tr>") != len(table)+1 {
+		t.Errorf("expected a row per team and a header row")
+	}
+}
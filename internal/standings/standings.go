@@ -0,0 +1,248 @@
+package standings
+
+import (
+	"sort"
+
+	"github.com/thomasoddsund/hockeykalender/internal/ehl"
+)
+
+// Points awarded per game under Norwegian league rules
+const (
+	PointsRegulationWin  = 3
+	PointsOvertimeWin    = 2 // Also for shootout wins
+	PointsOvertimeLoss   = 1 // Also for shootout losses
+	PointsRegulationLoss = 0
+)
+
+// Row is one team's line in the table
+type Row struct {
+	Position         int
+	Team             ehl.Team
+	Played           int
+	RegulationWins   int
+	OvertimeWins     int // Wins in overtime or shootout
+	OvertimeLosses   int // Losses in overtime or shootout
+	RegulationLosses int
+	GoalsFor         int
+	GoalsAgainst     int
+	Points           int
+}
+
+// GoalDifference is goals for minus goals against
+func (r Row) GoalDifference() int {
+	return r.GoalsFor - r.GoalsAgainst
+}
+
+// Table is a league table, ordered by position
+type Table []Row
+
+// Find returns the row of a team by short name, and false if the team is not in the table
+func (t Table) Find(teamName string) (Row, bool) {
+	for _, row := range t {
+		if row.Team.ShortName == teamName {
+			return row, true
+		}
+	}
+	return Row{}, false
+}
+
+// RegularSeason returns the games of a season that count towards the table, those of its
+// regular season game type. Series name their game types differently, so it is the type
+// recognised by ehl.GameType.IsRegularSeason, or else the type with the most games.
+// Games without a game type only count when no game has one.
+func RegularSeason(games []ehl.Game) []ehl.Game {
+	counts := make(map[string]int)
+	regularType, found := "", false
+	for _, game := range games {
+		counts[game.GameType.UUID]++
+		if !found && game.GameType.IsRegularSeason() {
+			regularType, found = game.GameType.UUID, true
+		}
+	}
+
+	if !found {
+		for uuid, count := range counts {
+			if uuid == "" {
+				continue
+			}
+			if !found || count > counts[regularType] || (count == counts[regularType] && uuid < regularType) {
+				regularType, found = uuid, true
+			}
+		}
+	}
+
+	var regular []ehl.Game
+	for _, game := range games {
+		if game.GameType.UUID == regularType {
+			regular = append(regular, game)
+		}
+	}
+	return regular
+}
+
+// Compute builds the table of a season from its games. Every team playing in
+// games gets a row; only finished games with a winner count.
+//
+// Teams are ranked by points, then goal difference, then goals scored, then points
+// in the games between the tied teams, then regulation wins, and finally by name.
+func Compute(games []ehl.Game) Table {
+	rows := make(map[string]*Row)
+	var order []string
+
+	row := func(team ehl.Team) *Row {
+		r, ok := rows[team.ShortName]
+		if !ok {
+			r = &Row{Team: team}
+			rows[team.ShortName] = r
+			order = append(order, team.ShortName)
+		}
+		return r
+	}
+
+	var counted []ehl.Game
+	for _, game := range games {
+		if game.IsCancelled() {
+			continue
+		}
+		home, away := row(game.HomeTeam), row(game.AwayTeam)
+		if !Counts(game) {
+			continue
+		}
+
+		home.record(game.HomeTeam.Score, game.AwayTeam.Score, game.Decision)
+		away.record(game.AwayTeam.Score, game.HomeTeam.Score, game.Decision)
+		counted = append(counted, game)
+	}
+
+	table := make(Table, 0, len(order))
+	for _, name := range order {
+		table = append(table, *rows[name])
+	}
+
+	sort.SliceStable(table, func(i, j int) bool {
+		return compareOverall(table[i], table[j]) < 0
+	})
+	breakTies(table, counted)
+
+	for i := range table {
+		table[i].Position = i + 1
+	}
+
+	return table
+}
+
+// Changed returns the names of the teams whose row differs between two tables, in the
+// order of after, followed by teams only in before
+func Changed(before, after Table) []string {
+	rows := make(map[string]Row, len(before))
+	for _, row := range before {
+		rows[row.Team.ShortName] = row
+	}
+
+	var changed []string
+	for _, row := range after {
+		old, ok := rows[row.Team.ShortName]
+		delete(rows, row.Team.ShortName)
+		if !ok || old != row {
+			changed = append(changed, row.Team.ShortName)
+		}
+	}
+	for _, row := range before {
+		if _, removed := rows[row.Team.ShortName]; removed {
+			changed = append(changed, row.Team.ShortName)
+		}
+	}
+	return changed
+}
+
+// Counts reports whether a game has a final result that counts in the table: it is
+// finished and one team is ahead on the reported score. Winners, shootouts included,
+// are only known from the score, so a game finished level cannot be scored.
+func Counts(game ehl.Game) bool {
+	return game.State == ehl.StatePostGame && game.HomeTeam.Score != game.AwayTeam.Score
+}
+
+// Points returns the points a team with goalsFor and goalsAgainst gets from a finished game
+func Points(goalsFor, goalsAgainst int, decision ehl.Decision) int {
+	switch {
+	case goalsFor > goalsAgainst && decision == ehl.DecisionRegulation:
+		return PointsRegulationWin
+	case goalsFor > goalsAgainst:
+		return PointsOvertimeWin
+	case decision == ehl.DecisionRegulation:
+		return PointsRegulationLoss
+	default:
+		return PointsOvertimeLoss
+	}
+}
+
+// record adds a finished game to the row
+func (r *Row) record(goalsFor, goalsAgainst int, decision ehl.Decision) {
+	r.Played++
+	r.GoalsFor += goalsFor
+	r.GoalsAgainst += goalsAgainst
+	r.Points += Points(goalsFor, goalsAgainst, decision)
+
+	won := goalsFor > goalsAgainst
+	switch {
+	case won && decision == ehl.DecisionRegulation:
+		r.RegulationWins++
+	case won:
+		r.OvertimeWins++
+	case decision == ehl.DecisionRegulation:
+		r.RegulationLosses++
+	default:
+		r.OvertimeLosses++
+	}
+}
+
+// compareOverall orders rows by points, goal difference and goals scored, best first
+func compareOverall(a, b Row) int {
+	if a.Points != b.Points {
+		return b.Points - a.Points
+	}
+	if a.GoalDifference() != b.GoalDifference() {
+		return b.GoalDifference() - a.GoalDifference()
+	}
+	return b.GoalsFor - a.GoalsFor
+}
+
+// breakTies orders groups of rows that are level overall by their points in the games
+// between them, then by regulation wins and name
+func breakTies(table Table, games []ehl.Game) {
+	for start := 0; start < len(table); {
+		end := start + 1
+		for end < len(table) && compareOverall(table[start], table[end]) == 0 {
+			end++
+		}
+
+		if group := table[start:end]; len(group) > 1 {
+			tied := make(map[string]bool, len(group))
+			for _, row := range group {
+				tied[row.Team.ShortName] = true
+			}
+
+			points := make(map[string]int, len(group))
+			for _, game := range games {
+				home, away := game.HomeTeam, game.AwayTeam
+				if tied[home.ShortName] && tied[away.ShortName] {
+					points[home.ShortName] += Points(home.Score, away.Score, game.Decision)
+					points[away.ShortName] += Points(away.Score, home.Score, game.Decision)
+				}
+			}
+
+			sort.SliceStable(group, func(i, j int) bool {
+				a, b := group[i], group[j]
+				if points[a.Team.ShortName] != points[b.Team.ShortName] {
+					return points[a.Team.ShortName] > points[b.Team.ShortName]
+				}
+				if a.RegulationWins != b.RegulationWins {
+					return a.RegulationWins > b.RegulationWins
+				}
+				return a.Team.ShortName < b.Team.ShortName
+			})
+		}
+
+		start = end
+	}
+}
@@ -0,0 +1,292 @@
+package standings
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/thomasoddsund/hockeykalender/internal/ehl"
+)
+
+var kickoff = time.Date(2025, 9, 11, 17, 0, 0, 0, time.UTC)
+
+// result is a compact fixture game: home and away team, score and decision
+func result(home, away string, homeScore, awayScore int, decision ehl.Decision) ehl.Game {
+	return ehl.Game{
+		UUID:      home + "-" + away,
+		StartTime: kickoff,
+		State:     ehl.StatePostGame,
+		Decision:  decision,
+		HomeTeam:  ehl.Team{UUID: home, ShortName: home, Score: homeScore},
+		AwayTeam:  ehl.Team{UUID: away, ShortName: away, Score: awayScore},
+	}
+}
+
+func upcoming(home, away string) ehl.Game {
+	game := result(home, away, 0, 0, ehl.DecisionRegulation)
+	game.State = ehl.StatePreGame
+	return game
+}
+
+func TestPoints(t *testing.T) {
+	tests := []struct {
+		name         string
+		goalsFor     int
+		goalsAgainst int
+		decision     ehl.Decision
+		expected     int
+	}{
+		{"regulation win", 4, 1, ehl.DecisionRegulation, 3},
+		{"overtime win", 3, 2, ehl.DecisionOvertime, 2},
+		{"shootout win", 2, 1, ehl.DecisionShootout, 2},
+		{"overtime loss", 2, 3, ehl.DecisionOvertime, 1},
+		{"shootout loss", 1, 2, ehl.DecisionShootout, 1},
+		{"regulation loss", 0, 5, ehl.DecisionRegulation, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Points(tt.goalsFor, tt.goalsAgainst, tt.decision); got != tt.expected {
+				t.Errorf("Points() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCompute(t *testing.T) {
+	tests := []struct {
+		name     string
+		games    []ehl.Game
+		expected []string // Team names by position
+		points   []int
+	}{
+		{
+			name:     "season not started",
+			games:    []ehl.Game{upcoming("Vålerenga", "Storhamar"), upcoming("Narvik", "Sparta")},
+			expected: []string{"Narvik", "Sparta", "Storhamar", "Vålerenga"},
+			points:   []int{0, 0, 0, 0},
+		},
+		{
+			name: "points",
+			games: []ehl.Game{
+				result("Vålerenga", "Storhamar", 3, 2, ehl.DecisionOvertime),
+				result("Storhamar", "Narvik", 5, 1, ehl.DecisionRegulation),
+				result("Narvik", "Vålerenga", 1, 2, ehl.DecisionShootout),
+			},
+			expected: []string{"Storhamar", "Vålerenga", "Narvik"},
+			points:   []int{4, 4, 1},
+		},
+		{
+			name: "goal difference",
+			games: []ehl.Game{
+				result("Vålerenga", "Narvik", 6, 0, ehl.DecisionRegulation),
+				result("Storhamar", "Narvik", 2, 1, ehl.DecisionRegulation),
+			},
+			expected: []string{"Vålerenga", "Storhamar", "Narvik"},
+			points:   []int{3, 3, 0},
+		},
+		{
+			name: "goals scored",
+			games: []ehl.Game{
+				result("Vålerenga", "Narvik", 5, 3, ehl.DecisionRegulation),
+				result("Storhamar", "Narvik", 2, 0, ehl.DecisionRegulation),
+			},
+			expected: []string{"Vålerenga", "Storhamar", "Narvik"},
+			points:   []int{3, 3, 0},
+		},
+		{
+			name: "level on everything",
+			games: []ehl.Game{
+				result("Vålerenga", "Narvik", 2, 1, ehl.DecisionRegulation),
+				result("Storhamar", "Narvik", 2, 1, ehl.DecisionRegulation),
+			},
+			expected: []string{"Storhamar", "Vålerenga", "Narvik"},
+			points:   []int{3, 3, 0},
+		},
+		{
+			name: "shootouts",
+			games: []ehl.Game{
+				// The score includes the deciding shootout goal
+				result("Vålerenga", "Storhamar", 2, 3, ehl.DecisionShootout),
+				// A level score has no winner, so the game cannot be scored
+				result("Narvik", "Sparta", 0, 0, ehl.DecisionShootout),
+			},
+			expected: []string{"Storhamar", "Vålerenga", "Narvik", "Sparta"},
+			points:   []int{2, 1, 0, 0},
+		},
+		{
+			name: "cancelled and unfinished games",
+			games: []ehl.Game{
+				result("Vålerenga", "Storhamar", 4, 1, ehl.DecisionRegulation),
+				func() ehl.Game {
+					g := result("Storhamar", "Vålerenga", 9, 0, ehl.DecisionRegulation)
+					g.State = ehl.StateLive
+					return g
+				}(),
+				func() ehl.Game {
+					g := result("Narvik", "Storhamar", 9, 0, ehl.DecisionRegulation)
+					g.State = ehl.StateCancelled
+					return g
+				}(),
+			},
+			expected: []string{"Vålerenga", "Storhamar"},
+			points:   []int{3, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := Compute(tt.games)
+
+			var names []string
+			var points []int
+			for i, row := range table {
+				if row.Position != i+1 {
+					t.Errorf("row %d has position %d", i, row.Position)
+				}
+				names = append(names, row.Team.ShortName)
+				points = append(points, row.Points)
+			}
+			if !slices.Equal(names, tt.expected) {
+				t.Errorf("order = %v, want %v", names, tt.expected)
+			}
+			if !slices.Equal(points, tt.points) {
+				t.Errorf("points = %v, want %v", points, tt.points)
+			}
+		})
+	}
+}
+
+func TestCompute_HeadToHead(t *testing.T) {
+	// Vålerenga and Storhamar end level overall, but Vålerenga won their meeting
+	games := []ehl.Game{
+		result("Storhamar", "Narvik", 4, 2, ehl.DecisionRegulation),
+		result("Vålerenga", "Storhamar", 2, 1, ehl.DecisionRegulation),
+		result("Narvik", "Vålerenga", 3, 2, ehl.DecisionRegulation),
+		result("Lillehammer", "Storhamar", 1, 2, ehl.DecisionRegulation),
+		result("Vålerenga", "Lillehammer", 3, 1, ehl.DecisionRegulation),
+	}
+
+	table := Compute(games)
+
+	first, second := table[0], table[1]
+	if first.Points != second.Points || first.GoalDifference() != second.GoalDifference() || first.GoalsFor != second.GoalsFor {
+		t.Fatalf("fixture should tie the top two overall: %+v, %+v", first, second)
+	}
+	if first.Team.ShortName != "Vålerenga" {
+		t.Errorf("expected Vålerenga first on head-to-head, got %s", first.Team.ShortName)
+	}
+}
+
+func TestCompute_Row(t *testing.T) {
+	games := []ehl.Game{
+		result("Vålerenga", "Storhamar", 4, 1, ehl.DecisionRegulation),
+		result("Storhamar", "Vålerenga", 3, 2, ehl.DecisionOvertime),
+		result("Vålerenga", "Storhamar", 1, 2, ehl.DecisionRegulation),
+		result("Storhamar", "Vålerenga", 2, 3, ehl.DecisionShootout),
+		upcoming("Vålerenga", "Storhamar"),
+	}
+
+	row, ok := Compute(games).Find("Vålerenga")
+	if !ok {
+		t.Fatal("expected Vålerenga in the table")
+	}
+
+	expected := Row{
+		Position:         1,
+		Team:             row.Team,
+		Played:           4,
+		RegulationWins:   1,
+		OvertimeWins:     1,
+		OvertimeLosses:   1,
+		RegulationLosses: 1,
+		GoalsFor:         10,
+		GoalsAgainst:     8,
+		Points:           6,
+	}
+	if row != expected {
+		t.Errorf("expected %+v, got %+v", expected, row)
+	}
+	if row.GoalDifference() != 2 {
+		t.Errorf("expected goal difference 2, got %d", row.GoalDifference())
+	}
+
+	if _, ok := Compute(games).Find("Narvik"); ok {
+		t.Error("expected no row for a team without games")
+	}
+}
+
+func TestChanged(t *testing.T) {
+	games := []ehl.Game{
+		result("Vålerenga", "Storhamar", 3, 1, ehl.DecisionRegulation),
+		result("Narvik", "Sparta", 2, 1, ehl.DecisionRegulation),
+		result("Lillehammer", "Frisk", 2, 1, ehl.DecisionRegulation),
+		upcoming("Storhamar", "Narvik"),
+	}
+	before := Compute(games)
+
+	// Narvik win and go top, moving Vålerenga and Lillehammer down a place without playing
+	played := games[3]
+	played.State, played.AwayTeam.Score = ehl.StatePostGame, 5
+	after := Compute(append(games[:3:3], played))
+
+	got := Changed(before, after)
+	slices.Sort(got)
+	expected := []string{"Lillehammer", "Narvik", "Storhamar", "Vålerenga"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("Changed() = %v, want %v", got, expected)
+	}
+
+	if got := Changed(before, before); len(got) != 0 {
+		t.Errorf("expected no changes between equal tables, got %v", got)
+	}
+}
+
+func TestRegularSeason(t *testing.T) {
+	typed := func(uuid, name string) ehl.Game {
+		game := result("Vålerenga", "Storhamar", 1, 0, "")
+		game.UUID = uuid + "-" + name
+		game.GameType = ehl.GameType{UUID: uuid, Name: name}
+		return game
+	}
+	untyped := result("Narvik", "Sparta", 1, 0, "")
+
+	tests := []struct {
+		name     string
+		games    []ehl.Game
+		expected []string // Game type UUIDs of the returned games
+	}{
+		{
+			name:     "EHL regular season",
+			games:    []ehl.Game{typed(ehl.GameTypeUUID, "Serie"), typed("playoffs", "Sluttspill"), untyped},
+			expected: []string{ehl.GameTypeUUID},
+		},
+		{
+			name:     "other series by name",
+			games:    []ehl.Game{typed("women-playoffs", "Sluttspill"), typed("women-regular", "Grunnserie"), typed("women-regular", "Grunnserie")},
+			expected: []string{"women-regular", "women-regular"},
+		},
+		{
+			name:     "unknown names use the type with the most games",
+			games:    []ehl.Game{typed("a", "Kvalik"), typed("b", "Runde 1"), typed("b", "Runde 1"), untyped},
+			expected: []string{"b", "b"},
+		},
+		{
+			name:     "untyped games",
+			games:    []ehl.Game{untyped, untyped},
+			expected: []string{"", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, game := range RegularSeason(tt.games) {
+				got = append(got, game.GameType.UUID)
+			}
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("RegularSeason() types = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
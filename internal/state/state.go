@@ -0,0 +1,308 @@
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/thomasoddsund/hockeykalender/internal/diff"
+	"github.com/thomasoddsund/hockeykalender/internal/ehl"
+	"github.com/thomasoddsund/hockeykalender/internal/ical"
+)
+
+// Entry is the published state of a single game
+type Entry struct {
+	Season          string    `json:"season"`       // Season UUID, scopes removal detection
+	Game            ehl.Game  `json:"game"`         // Last known data, used to publish removed games
+	Hash            string    `json:"hash"`         // Hash of all game data
+	ScheduleHash    string    `json:"scheduleHash"` // Hash of start time, venue and cancellation
+	Sequence        int       `json:"sequence"`
+	Modified        time.Time `json:"modified"`
+	Cancelled       time.Time `json:"cancelled,omitzero"`        // When the game was first seen cancelled or removed
+	DescriptionHash string    `json:"descriptionHash,omitempty"` // Hash of the DESCRIPTION, which can depend on other games
+}
+
+// State tracks per-game content hashes and published games between generator runs,
+// so events only change when their game does and removed games can be announced
+type State struct {
+	Games   map[string]Entry `json:"games"`
+	Changes diff.History     `json:"changes,omitempty"` // Recent schedule changes, for the Atom feeds
+
+	// AcceptRemovals lets Update remove games even when most of a season is missing,
+	// e.g. after the league reissued its schedule with new games
+	AcceptRemovals bool `json:"-"`
+}
+
+// New returns an empty state
+func New() *State {
+	return &State{Games: make(map[string]Entry)}
+}
+
+// Clone returns a copy of the state that can be updated without changing s
+func (s *State) Clone() *State {
+	c := &State{Games: make(map[string]Entry, len(s.Games)), Changes: slices.Clone(s.Changes), AcceptRemovals: s.AcceptRemovals}
+	maps.Copy(c.Games, s.Games)
+	return c
+}
+
+// Load reads a state file, returning an empty state if it does not exist
+func Load(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return New(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state: %w", err)
+	}
+
+	s := New()
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to decode state: %w", err)
+	}
+	if s.Games == nil {
+		s.Games = make(map[string]Entry)
+	}
+
+	return s, nil
+}
+
+// Save writes the state file, creating its directory if needed. The file is written to a
+// temporary file first and renamed over the old one, so an interrupted save leaves it intact.
+func (s *State) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create state file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // No-op after the rename
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace state: %w", err)
+	}
+	return nil
+}
+
+// maxRemovedShare is the share of a season's scheduled games that may disappear in a single
+// update. Schedules lose a few games at a time; a larger loss is more likely an API glitch.
+const maxRemovedShare = 0.5
+
+// ErrMassRemoval is returned by Update when too many games are missing from a season
+var ErrMassRemoval = errors.New("too many games missing from the schedule")
+
+// Update records the complete list of games of a season and returns the games to publish.
+//
+// A game's modification time moves to now only when its data changed, and its sequence
+// increases when its time, venue or cancellation changed. Previously published games
+// missing from games are returned with state ehl.StateRemoved. Cancelled, postponed and
+// removed games are published for grace after they were first seen as such, then dropped.
+//
+// If games is empty, or more than half of the season's scheduled games are missing from it,
+// the missing games are left alone rather than all cancelled: only games is published and
+// ErrMassRemoval is returned along with it. Set AcceptRemovals to remove them anyway.
+func (s *State) Update(seasonUUID string, games []ehl.Game, now time.Time, grace time.Duration) ([]ehl.Game, error) {
+	now = now.UTC().Truncate(time.Second)
+	present := uuids(games)
+	err := s.massRemoval(seasonUUID, present)
+
+	for _, game := range games {
+		s.record(seasonUUID, game, now)
+	}
+
+	// Games that disappeared from the schedule
+	for uuid, entry := range s.Games {
+		if err != nil || entry.Season != seasonUUID || present[uuid] || entry.Game.State == ehl.StateRemoved {
+			continue
+		}
+		entry.Game.State = ehl.StateRemoved
+		s.record(seasonUUID, entry.Game, now)
+	}
+
+	var published []ehl.Game
+	for _, game := range games {
+		if s.visible(game.UUID, now, grace) {
+			published = append(published, game)
+		}
+	}
+	for uuid, entry := range s.Games {
+		if err != nil || entry.Season != seasonUUID || present[uuid] {
+			continue
+		}
+		if s.visible(uuid, now, grace) {
+			published = append(published, entry.Game)
+		} else {
+			delete(s.Games, uuid)
+		}
+	}
+
+	return ehl.MergeSeasonGames([]ehl.SeasonGames{{Games: published}}), err
+}
+
+// massRemoval returns ErrMassRemoval if so many of a season's scheduled games are missing
+// from present that the schedule is more likely broken than changed, unless AcceptRemovals
+func (s *State) massRemoval(seasonUUID string, present map[string]bool) error {
+	if s.AcceptRemovals {
+		return nil
+	}
+
+	scheduled, missing := 0, 0
+	for uuid, entry := range s.Games {
+		if entry.Season != seasonUUID || entry.Game.State == ehl.StateRemoved {
+			continue
+		}
+		scheduled++
+		if !present[uuid] {
+			missing++
+		}
+	}
+	if scheduled > 0 && (len(present) == 0 || (missing > 1 && float64(missing) > maxRemovedShare*float64(scheduled))) {
+		return fmt.Errorf("%w: %d of %d games of season %s", ErrMassRemoval, missing, scheduled, seasonUUID)
+	}
+	return nil
+}
+
+// uuids returns the set of UUIDs of games
+func uuids(games []ehl.Game) map[string]bool {
+	present := make(map[string]bool, len(games))
+	for _, game := range games {
+		present[game.UUID] = true
+	}
+	return present
+}
+
+// HasSeason reports whether any game of a season has been recorded
+func (s *State) HasSeason(seasonUUID string) bool {
+	for _, entry := range s.Games {
+		if entry.Season == seasonUUID {
+			return true
+		}
+	}
+	return false
+}
+
+// Compare returns the changes from the recorded games of a season to games. Without
+// recorded games, on the first run, after the state was lost or in a new season, every
+// game would be reported as added, so there are no changes. Games that Update would leave
+// alone because most of the season is missing are not reported as removed either.
+func (s *State) Compare(seasonUUID string, games []ehl.Game) []diff.Change {
+	if !s.HasSeason(seasonUUID) {
+		return nil
+	}
+
+	previous := s.SeasonGames(seasonUUID)
+	if present := uuids(games); s.massRemoval(seasonUUID, present) != nil {
+		previous = slices.DeleteFunc(previous, func(game ehl.Game) bool { return !present[game.UUID] })
+	}
+	return diff.Compare(previous, games)
+}
+
+// UpdateDescriptions records the DESCRIPTION of games, from ical.Descriptions. A game's
+// modification time moves to now when its description changed, e.g. because another
+// result changed the table, so calendar apps pick up the new text. The sequence stays,
+// as for other changes that leave the time and venue alone.
+func (s *State) UpdateDescriptions(descriptions map[string]string, now time.Time) {
+	now = now.UTC().Truncate(time.Second)
+
+	for uuid, description := range descriptions {
+		entry, ok := s.Games[uuid]
+		if !ok {
+			continue
+		}
+		hash := hashOf(description)
+		if entry.DescriptionHash != "" && entry.DescriptionHash != hash {
+			entry.Modified = now
+		}
+		entry.DescriptionHash = hash
+		s.Games[uuid] = entry
+	}
+}
+
+// SeasonGames returns the last known data of every game of a season, including
+// cancelled and removed ones, ordered by start time
+func (s *State) SeasonGames(seasonUUID string) []ehl.Game {
+	var games []ehl.Game
+	for _, entry := range s.Games {
+		if entry.Season == seasonUUID {
+			games = append(games, entry.Game)
+		}
+	}
+	return ehl.MergeSeasonGames([]ehl.SeasonGames{{Games: games}})
+}
+
+// record stores the current data of a game, bumping its revision if it changed
+func (s *State) record(seasonUUID string, game ehl.Game, now time.Time) {
+	hash := hashOf(game)
+	scheduleHash := hashOf(struct {
+		StartTime time.Time
+		Venue     string
+		Cancelled bool
+	}{game.StartTime.UTC(), game.Venue.Name, game.IsCancelled()})
+
+	entry, exists := s.Games[game.UUID]
+	switch {
+	case !exists:
+		entry = Entry{Hash: hash, ScheduleHash: scheduleHash, Modified: now}
+	case entry.Hash != hash:
+		if entry.ScheduleHash != scheduleHash {
+			entry.Sequence++
+		}
+		entry.Hash = hash
+		entry.ScheduleHash = scheduleHash
+		entry.Modified = now
+	}
+
+	if !game.IsCancelled() {
+		entry.Cancelled = time.Time{}
+	} else if entry.Cancelled.IsZero() {
+		entry.Cancelled = now
+	}
+
+	entry.Season = seasonUUID
+	entry.Game = game
+	s.Games[game.UUID] = entry
+}
+
+// visible reports whether a game is active, or cancelled within the grace period
+func (s *State) visible(uuid string, now time.Time, grace time.Duration) bool {
+	entry := s.Games[uuid]
+	return entry.Cancelled.IsZero() || now.Sub(entry.Cancelled) < grace
+}
+
+// Revisions returns the revision of every known game for ical.WithRevisions
+func (s *State) Revisions() map[string]ical.Revision {
+	revisions := make(map[string]ical.Revision, len(s.Games))
+	for uuid, entry := range s.Games {
+		revisions[uuid] = ical.Revision{Sequence: entry.Sequence, Modified: entry.Modified}
+	}
+	return revisions
+}
+
+func hashOf(v any) string {
+	data, _ := json.Marshal(v)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
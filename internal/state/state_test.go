@@ -0,0 +1,410 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/thomasoddsund/hockeykalender/internal/diff"
+	"github.com/thomasoddsund/hockeykalender/internal/ehl"
+	"github.com/thomasoddsund/hockeykalender/internal/ical"
+)
+
+const grace = 14 * 24 * time.Hour
+
+func testGame() ehl.Game {
+	return ehl.Game{
+		UUID:      "game-1",
+		StartTime: time.Date(2025, 9, 11, 17, 0, 0, 0, time.UTC),
+		State:     "pre-game",
+		HomeTeam:  ehl.Team{UUID: "team-vif", ShortName: "Vålerenga"},
+		AwayTeam:  ehl.Team{UUID: "team-sth", ShortName: "Storhamar"},
+		Venue:     ehl.Venue{Name: "Jordal Amfi"},
+	}
+}
+
+// update calls Update, failing the test on error
+func update(t *testing.T, s *State, seasonUUID string, games []ehl.Game, now time.Time, grace time.Duration) []ehl.Game {
+	t.Helper()
+	published, err := s.Update(seasonUUID, games, now, grace)
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	return published
+}
+
+func TestUpdate(t *testing.T) {
+	day1 := time.Date(2025, 9, 1, 6, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	day3 := day1.AddDate(0, 0, 2)
+	day4 := day1.AddDate(0, 0, 3)
+
+	s := New()
+	game := testGame()
+
+	update(t, s, "season-2526", []ehl.Game{game}, day1, grace)
+	if entry := s.Games["game-1"]; entry.Sequence != 0 || !entry.Modified.Equal(day1) {
+		t.Errorf("new game: got sequence %d, modified %v", entry.Sequence, entry.Modified)
+	}
+
+	// Unchanged data keeps the modification time
+	update(t, s, "season-2526", []ehl.Game{game}, day2, grace)
+	if entry := s.Games["game-1"]; !entry.Modified.Equal(day1) {
+		t.Errorf("unchanged game: expected modified %v, got %v", day1, entry.Modified)
+	}
+
+	// A result moves the modification time but not the sequence
+	game.State = "post-game"
+	game.HomeTeam.Score = 3
+	update(t, s, "season-2526", []ehl.Game{game}, day3, grace)
+	if entry := s.Games["game-1"]; entry.Sequence != 0 || !entry.Modified.Equal(day3) {
+		t.Errorf("result: got sequence %d, modified %v", entry.Sequence, entry.Modified)
+	}
+
+	// A new time increases the sequence
+	game.StartTime = game.StartTime.Add(time.Hour)
+	update(t, s, "season-2526", []ehl.Game{game}, day4, grace)
+	if entry := s.Games["game-1"]; entry.Sequence != 1 || !entry.Modified.Equal(day4) {
+		t.Errorf("rescheduled: got sequence %d, modified %v", entry.Sequence, entry.Modified)
+	}
+
+	// So does a new venue
+	game.Venue.Name = "Furuset Forum"
+	update(t, s, "season-2526", []ehl.Game{game}, day4, grace)
+	if entry := s.Games["game-1"]; entry.Sequence != 2 {
+		t.Errorf("venue change: got sequence %d, want 2", entry.Sequence)
+	}
+}
+
+func TestLoadSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "ehl.json")
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load of missing file failed: %v", err)
+	}
+	if len(s.Games) != 0 {
+		t.Errorf("expected empty state, got %d games", len(s.Games))
+	}
+
+	update(t, s, "season-2526", []ehl.Game{testGame()}, time.Date(2025, 9, 1, 6, 0, 0, 0, time.UTC), grace)
+	if err := s.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.Games["game-1"] != s.Games["game-1"] {
+		t.Errorf("loaded entry %+v, want %+v", loaded.Games["game-1"], s.Games["game-1"])
+	}
+
+	// Saving again replaces the file without leaving temporary files behind
+	if err := loaded.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if files, _ := os.ReadDir(filepath.Dir(path)); len(files) != 1 {
+		t.Errorf("expected only the state file, got %d files", len(files))
+	}
+}
+
+func TestReproducibleCalendar(t *testing.T) {
+	seasons := []ehl.SeasonGames{{Season: ehl.Season{Name: "2025/2026"}, Games: []ehl.Game{testGame()}}}
+
+	s := New()
+	update(t, s, "season-2526", seasons[0].Games, time.Date(2025, 9, 1, 6, 0, 0, 0, time.UTC), grace)
+	first := ical.GenerateCalendar(seasons, ical.Filter{}, nil, "EHL", ical.WithRevisions(s.Revisions()))
+
+	// A later run with identical input
+	update(t, s, "season-2526", seasons[0].Games, time.Date(2025, 9, 2, 6, 0, 0, 0, time.UTC), grace)
+	second := ical.GenerateCalendar(seasons, ical.Filter{}, nil, "EHL", ical.WithRevisions(s.Revisions()))
+
+	if first != second {
+		t.Errorf("expected identical output for identical input:\n%s\n---\n%s", first, second)
+	}
+}
+
+func TestUpdate_RemovedGame(t *testing.T) {
+	day1 := time.Date(2025, 9, 1, 6, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	s := New()
+	game := testGame()
+	other := testGame()
+	other.UUID = "game-2"
+
+	update(t, s, "season-2526", []ehl.Game{game, other}, day1, grace)
+
+	// game-1 disappears from the schedule
+	published := update(t, s, "season-2526", []ehl.Game{other}, day2, grace)
+	if len(published) != 2 {
+		t.Fatalf("expected removed game to stay published, got %d games", len(published))
+	}
+
+	var removed ehl.Game
+	for _, g := range published {
+		if g.UUID == "game-1" {
+			removed = g
+		}
+	}
+	if removed.State != ehl.StateRemoved {
+		t.Errorf("expected state %q, got %q", ehl.StateRemoved, removed.State)
+	}
+	if removed.Venue.Name != "Jordal Amfi" {
+		t.Error("expected the last known game data")
+	}
+	if entry := s.Games["game-1"]; entry.Sequence != 1 || !entry.Modified.Equal(day2) {
+		t.Errorf("expected removal to bump the revision, got sequence %d, modified %v", entry.Sequence, entry.Modified)
+	}
+
+	// Still published, unchanged, within the grace period
+	later := update(t, s, "season-2526", []ehl.Game{other}, day2.Add(grace-time.Hour), grace)
+	if len(later) != 2 {
+		t.Errorf("expected removed game within grace period, got %d games", len(later))
+	}
+	if entry := s.Games["game-1"]; entry.Sequence != 1 {
+		t.Errorf("expected no further revision, got sequence %d", entry.Sequence)
+	}
+
+	// Dropped and forgotten after the grace period
+	expired := update(t, s, "season-2526", []ehl.Game{other}, day2.Add(grace), grace)
+	if len(expired) != 1 {
+		t.Errorf("expected removed game to be dropped after grace period, got %d games", len(expired))
+	}
+	if _, ok := s.Games["game-1"]; ok {
+		t.Error("expected expired game to be removed from state")
+	}
+}
+
+func TestUpdate_OtherSeasonNotRemoved(t *testing.T) {
+	day1 := time.Date(2025, 9, 1, 6, 0, 0, 0, time.UTC)
+
+	s := New()
+	update(t, s, "season-2425", []ehl.Game{testGame()}, day1, grace)
+
+	other := testGame()
+	other.UUID = "game-2"
+	published := update(t, s, "season-2526", []ehl.Game{other}, day1, grace)
+
+	if len(published) != 1 || published[0].UUID != "game-2" {
+		t.Errorf("expected only the updated season's games, got %v", published)
+	}
+	if s.Games["game-1"].Game.State == ehl.StateRemoved {
+		t.Error("expected games of other seasons to be left alone")
+	}
+}
+
+func TestUpdate_CancelledGame(t *testing.T) {
+	day1 := time.Date(2025, 9, 1, 6, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	s := New()
+	game := testGame()
+	update(t, s, "season-2526", []ehl.Game{game}, day1, grace)
+
+	game.State = ehl.StatePostponed
+	published := update(t, s, "season-2526", []ehl.Game{game}, day2, grace)
+	if len(published) != 1 || published[0].State != ehl.StatePostponed {
+		t.Errorf("expected postponed game to be published, got %v", published)
+	}
+	if entry := s.Games["game-1"]; entry.Sequence != 1 || !entry.Cancelled.Equal(day2) {
+		t.Errorf("expected postponement to bump sequence, got sequence %d, cancelled %v", entry.Sequence, entry.Cancelled)
+	}
+
+	if published := update(t, s, "season-2526", []ehl.Game{game}, day2.Add(grace), grace); len(published) != 0 {
+		t.Errorf("expected postponed game to be dropped after grace period, got %d games", len(published))
+	}
+}
+
+func TestSeasonGames(t *testing.T) {
+	now := time.Date(2025, 9, 1, 6, 0, 0, 0, time.UTC)
+
+	s := New()
+	game := testGame()
+	other := testGame()
+	other.UUID = "game-2"
+
+	third := testGame()
+	third.UUID = "game-3"
+	third.StartTime = game.StartTime.AddDate(0, 0, 1)
+
+	update(t, s, "season-2526", []ehl.Game{game, third}, now, grace)
+	update(t, s, "season-2425", []ehl.Game{other}, now, grace)
+
+	games := s.SeasonGames("season-2526")
+	if len(games) != 2 || games[0].UUID != "game-1" || games[1].UUID != "game-3" {
+		t.Errorf("expected game-1 and game-3, got %+v", games)
+	}
+
+	// Removed games are still returned, with their removed state
+	update(t, s, "season-2526", []ehl.Game{third}, now, grace)
+	games = s.SeasonGames("season-2526")
+	if len(games) != 2 || games[0].State != ehl.StateRemoved {
+		t.Errorf("expected removed game-1, got %+v", games)
+	}
+
+	if games := s.SeasonGames("unknown"); len(games) != 0 {
+		t.Errorf("expected no games for unknown season, got %d", len(games))
+	}
+}
+
+func TestCompare(t *testing.T) {
+	now := time.Date(2025, 9, 1, 6, 0, 0, 0, time.UTC)
+	game := testGame()
+	added := testGame()
+	added.UUID = "game-2"
+
+	s := New()
+	if changes := s.Compare("season-2526", []ehl.Game{game}); len(changes) != 0 {
+		t.Errorf("expected no changes without recorded games, got %+v", changes)
+	}
+
+	update(t, s, "season-2526", []ehl.Game{game}, now, grace)
+	changes := s.Compare("season-2526", []ehl.Game{game, added})
+	if len(changes) != 1 || changes[0].Type != diff.Added || changes[0].GameUUID != "game-2" {
+		t.Errorf("expected game-2 added, got %+v", changes)
+	}
+
+	// A season without recorded games, e.g. the next one, reports nothing either
+	if changes := s.Compare("season-2627", []ehl.Game{added}); len(changes) != 0 {
+		t.Errorf("expected no changes for a new season, got %+v", changes)
+	}
+}
+
+func TestCompare_History(t *testing.T) {
+	now := time.Date(2025, 9, 1, 6, 0, 0, 0, time.UTC)
+	games := []ehl.Game{testGame()}
+
+	// The Atom feeds are rendered from the history, which must not fill up with every game
+	// on the first run or after the state was lost
+	s := New()
+	s.Changes = s.Changes.Add(s.Compare("season-2526", games), now, now.AddDate(0, 0, -30))
+	update(t, s, "season-2526", games, now, grace)
+	if len(s.Changes) != 0 {
+		t.Errorf("expected no history without recorded games, got %+v", s.Changes)
+	}
+
+	moved := testGame()
+	moved.StartTime = moved.StartTime.Add(time.Hour)
+	s.Changes = s.Changes.Add(s.Compare("season-2526", []ehl.Game{moved}), now, now.AddDate(0, 0, -30))
+	if len(s.Changes) != 1 {
+		t.Errorf("expected the moved game in the history, got %+v", s.Changes)
+	}
+}
+
+func TestClone(t *testing.T) {
+	now := time.Date(2025, 9, 1, 6, 0, 0, 0, time.UTC)
+	s := New()
+	update(t, s, "season-2526", []ehl.Game{testGame()}, now, grace)
+
+	c := s.Clone()
+	moved := testGame()
+	moved.StartTime = moved.StartTime.Add(time.Hour)
+	c.Changes = c.Changes.Add(c.Compare("season-2526", []ehl.Game{moved}), now, now.AddDate(0, 0, -30))
+	update(t, c, "season-2526", []ehl.Game{moved}, now.Add(time.Hour), grace)
+
+	if entry := s.Games["game-1"]; entry.Sequence != 0 || !entry.Game.StartTime.Equal(testGame().StartTime) {
+		t.Errorf("expected the original state unchanged, got %+v", entry)
+	}
+	if len(s.Changes) != 0 {
+		t.Errorf("expected no changes in the original state, got %+v", s.Changes)
+	}
+	if c.Games["game-1"].Sequence != 1 || len(c.Changes) != 1 {
+		t.Errorf("expected the clone updated, got %+v", c.Games["game-1"])
+	}
+}
+
+func TestUpdateDescriptions(t *testing.T) {
+	day1 := time.Date(2025, 9, 1, 6, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	day3 := day1.AddDate(0, 0, 2)
+
+	s := New()
+	update(t, s, "season-2526", []ehl.Game{testGame()}, day1, grace)
+
+	// The first description is recorded without changing the revision
+	s.UpdateDescriptions(map[string]string{"game-1": "Tabell: Vålerenga 1. plass (3 poeng)"}, day2)
+	if entry := s.Games["game-1"]; !entry.Modified.Equal(day1) {
+		t.Errorf("expected modified unchanged, got %v", entry.Modified)
+	}
+
+	s.UpdateDescriptions(map[string]string{"game-1": "Tabell: Vålerenga 1. plass (3 poeng)", "unknown": "x"}, day2)
+	if entry := s.Games["game-1"]; !entry.Modified.Equal(day1) {
+		t.Errorf("expected an unchanged description to keep the revision, got %v", entry.Modified)
+	}
+	if _, ok := s.Games["unknown"]; ok {
+		t.Error("expected no entry for a game that is not recorded")
+	}
+
+	// Another game's result moved the team down the table
+	s.UpdateDescriptions(map[string]string{"game-1": "Tabell: Vålerenga 2. plass (3 poeng)"}, day3)
+	if entry := s.Games["game-1"]; !entry.Modified.Equal(day3) || entry.Sequence != 0 {
+		t.Errorf("expected modified %v and sequence 0, got %v and %d", day3, entry.Modified, entry.Sequence)
+	}
+}
+
+func TestUpdate_MassRemoval(t *testing.T) {
+	now := time.Date(2025, 9, 1, 6, 0, 0, 0, time.UTC)
+	games := make([]ehl.Game, 6)
+	for i := range games {
+		games[i] = testGame()
+		games[i].UUID = fmt.Sprintf("game-%d", i+1)
+	}
+
+	tests := []struct {
+		name    string
+		games   []ehl.Game
+		accept  bool
+		wantErr bool
+		removed int
+	}{
+		{"empty schedule", nil, false, true, 0},
+		{"most games missing", games[:2], false, true, 0},
+		{"most games missing, accepted", games[:2], true, false, 4},
+		{"half the games missing", games[:3], false, false, 3},
+		{"one game missing", games[:5], false, false, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New()
+			update(t, s, "season-2526", games, now, grace)
+			s.AcceptRemovals = tt.accept
+
+			changes := s.Compare("season-2526", tt.games)
+			published, err := s.Update("season-2526", tt.games, now.Add(time.Hour), grace)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Update() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrMassRemoval) {
+				t.Errorf("expected ErrMassRemoval, got %v", err)
+			}
+
+			// The fetched games are published either way, the missing ones only when removed
+			if len(published) != len(tt.games)+tt.removed {
+				t.Errorf("expected %d published games, got %d", len(tt.games)+tt.removed, len(published))
+			}
+			removed := 0
+			for _, entry := range s.Games {
+				if entry.Game.State == ehl.StateRemoved {
+					removed++
+				}
+			}
+			if removed != tt.removed {
+				t.Errorf("expected %d removed games, got %d", tt.removed, removed)
+			}
+			if len(changes) != tt.removed {
+				t.Errorf("expected %d removal changes, got %+v", tt.removed, changes)
+			}
+		})
+	}
+
+	// A new season may start empty
+	if _, err := New().Update("season-2627", nil, now, grace); err != nil {
+		t.Errorf("expected no error without recorded games, got %v", err)
+	}
+}
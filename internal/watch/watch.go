@@ -0,0 +1,124 @@
+package watch
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/thomasoddsund/hockeykalender/internal/ehl"
+)
+
+// Clock is the time source of the poll loop, replaced by a fake in tests
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// RealClock is the wall clock
+var RealClock Clock = realClock{}
+
+// Policy decides how often to poll the API
+type Policy struct {
+	Live        time.Duration // While a game is live or about to start
+	GameDay     time.Duration // While a game starts within the next 24 hours
+	Idle        time.Duration // Otherwise
+	Lead        time.Duration // How long before faceoff to start polling at the live interval
+	Overdue     time.Duration // How long after faceoff a game not yet reported live is still polled as live
+	MinInterval time.Duration // Rate limit: the shortest time between two polls
+}
+
+// DefaultPolicy polls every minute during games, every 15 minutes on game days and every 6 hours otherwise
+func DefaultPolicy() Policy {
+	return Policy{
+		Live:        time.Minute,
+		GameDay:     15 * time.Minute,
+		Idle:        6 * time.Hour,
+		Lead:        15 * time.Minute,
+		Overdue:     4 * time.Hour,
+		MinInterval: 30 * time.Second,
+	}
+}
+
+// Interval returns how long to wait before polling again, given the latest games
+func (p Policy) Interval(games []ehl.Game, now time.Time) time.Duration {
+	interval := p.Idle
+
+	for _, game := range games {
+		switch {
+		case game.State == ehl.StateLive:
+			return p.clamp(p.Live)
+		case game.State.Started() || game.IsCancelled():
+			continue
+		}
+
+		// Games that should be underway are polled as live until the API catches up
+		untilLead := game.StartTime.Add(-p.Lead).Sub(now)
+		if untilLead <= 0 && now.Before(game.StartTime.Add(p.Overdue)) {
+			return p.clamp(p.Live)
+		}
+		if untilLead <= 0 {
+			continue
+		}
+
+		// Wake up in time for the next game
+		if untilLead < 24*time.Hour {
+			interval = min(interval, p.GameDay)
+		}
+		interval = min(interval, untilLead)
+	}
+
+	return p.clamp(interval)
+}
+
+// clamp enforces the rate limit
+func (p Policy) clamp(d time.Duration) time.Duration {
+	return max(d, p.MinInterval)
+}
+
+// backoff returns the wait after failures consecutive failed polls, doubling from the
+// rate limit up to the idle interval
+func (p Policy) backoff(failures int) time.Duration {
+	d := p.clamp(p.MinInterval)
+	for i := 1; i < failures && d < p.Idle; i++ {
+		d *= 2
+	}
+	return min(d, max(p.Idle, p.MinInterval))
+}
+
+// Run polls until ctx is cancelled, waiting between polls as decided by policy.
+// games are the latest known games, which schedule the first poll. poll returns
+// the current games; when it fails, the previous games are kept and the next
+// poll backs off.
+func Run(ctx context.Context, clock Clock, policy Policy, games []ehl.Game, poll func(context.Context) ([]ehl.Game, error)) {
+	wait := policy.Interval(games, clock.Now())
+	failures := 0
+
+	for {
+		log.Printf("Next poll in %s", wait)
+		select {
+		case <-ctx.Done():
+			return
+		case <-clock.After(wait):
+		}
+
+		latest, err := poll(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			failures++
+			wait = policy.backoff(failures)
+			log.Printf("Poll failed (%d in a row): %v", failures, err)
+			continue
+		}
+
+		failures = 0
+		games = latest
+		wait = policy.Interval(games, clock.Now())
+	}
+}
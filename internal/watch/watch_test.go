@@ -0,0 +1,176 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/thomasoddsund/hockeykalender/internal/ehl"
+)
+
+// fakeClock jumps forward by every requested wait and records it
+type fakeClock struct {
+	now   time.Time
+	waits []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.waits = append(c.waits, d)
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+var start = time.Date(2025, 9, 11, 17, 0, 0, 0, time.UTC)
+
+func game(state ehl.GameState, startTime time.Time) ehl.Game {
+	return ehl.Game{UUID: "game-1", State: state, StartTime: startTime}
+}
+
+func TestInterval(t *testing.T) {
+	policy := DefaultPolicy()
+
+	tests := []struct {
+		name     string
+		games    []ehl.Game
+		now      time.Time
+		expected time.Duration
+	}{
+		{"no games", nil, start, 6 * time.Hour},
+		{"next game in a week", []ehl.Game{game(ehl.StatePreGame, start)}, start.AddDate(0, 0, -7), 6 * time.Hour},
+		{"game tonight", []ehl.Game{game(ehl.StatePreGame, start)}, start.Add(-5 * time.Hour), 15 * time.Minute},
+		{"wake up for faceoff", []ehl.Game{game(ehl.StatePreGame, start)}, start.Add(-20 * time.Hour), 15 * time.Minute},
+		{"day before", []ehl.Game{game(ehl.StatePreGame, start)}, start.Add(-26 * time.Hour), 6 * time.Hour},
+		{"just before faceoff", []ehl.Game{game(ehl.StatePreGame, start)}, start.Add(-20 * time.Minute), 5 * time.Minute},
+		{"about to start", []ehl.Game{game(ehl.StatePreGame, start)}, start.Add(-10 * time.Minute), time.Minute},
+		{"live", []ehl.Game{game(ehl.StateLive, start)}, start.Add(time.Hour), time.Minute},
+		{"late live report", []ehl.Game{game(ehl.StatePreGame, start)}, start.Add(time.Hour), time.Minute},
+		{"stale pre-game", []ehl.Game{game(ehl.StatePreGame, start)}, start.Add(5 * time.Hour), 6 * time.Hour},
+		{"finished", []ehl.Game{game(ehl.StatePostGame, start)}, start.Add(2 * time.Hour), 6 * time.Hour},
+		{"postponed", []ehl.Game{game(ehl.StatePostponed, start)}, start.Add(-10 * time.Minute), 6 * time.Hour},
+		{
+			"live among others",
+			[]ehl.Game{game(ehl.StatePostGame, start.Add(-time.Hour)), game(ehl.StatePreGame, start.AddDate(0, 0, 3)), game(ehl.StateLive, start)},
+			start.Add(30 * time.Minute),
+			time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Interval(tt.games, tt.now); got != tt.expected {
+				t.Errorf("Interval() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestInterval_RateLimit(t *testing.T) {
+	policy := DefaultPolicy()
+	policy.Live = time.Second
+
+	got := policy.Interval([]ehl.Game{game(ehl.StateLive, start)}, start)
+	if got != policy.MinInterval {
+		t.Errorf("Interval() = %s, want rate limit %s", got, policy.MinInterval)
+	}
+
+	// A game starting just after the lead time does not cause a burst of polls
+	got = policy.Interval([]ehl.Game{game(ehl.StatePreGame, start)}, start.Add(-policy.Lead-time.Second))
+	if got != policy.MinInterval {
+		t.Errorf("Interval() = %s, want rate limit %s", got, policy.MinInterval)
+	}
+}
+
+func TestRun(t *testing.T) {
+	clock := &fakeClock{now: start.Add(-30 * time.Minute)}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The game goes live, the API fails once, and the game ends
+	responses := []struct {
+		state ehl.GameState
+		err   error
+	}{
+		{ehl.StatePreGame, nil},
+		{ehl.StateLive, nil},
+		{ehl.StateLive, errors.New("503")},
+		{ehl.StateLive, nil},
+		{ehl.StatePostGame, nil},
+	}
+
+	var polled []time.Time
+	poll := func(context.Context) ([]ehl.Game, error) {
+		polled = append(polled, clock.now)
+		r := responses[len(polled)-1]
+		if len(polled) == len(responses) {
+			cancel()
+		}
+		return []ehl.Game{game(r.state, start)}, r.err
+	}
+
+	Run(ctx, clock, DefaultPolicy(), []ehl.Game{game(ehl.StatePreGame, start)}, poll)
+
+	expected := []time.Duration{
+		15 * time.Minute, // until the lead time
+		time.Minute,      // about to start
+		time.Minute,      // live
+		30 * time.Second, // backing off after the failure
+		time.Minute,      // live
+	}
+	if !slices.Equal(clock.waits, expected) {
+		t.Errorf("waits = %v, want %v", clock.waits, expected)
+	}
+	if len(polled) != len(responses) {
+		t.Errorf("expected %d polls, got %d", len(responses), len(polled))
+	}
+}
+
+func TestRun_Backoff(t *testing.T) {
+	clock := &fakeClock{now: start}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	polls := 0
+	poll := func(context.Context) ([]ehl.Game, error) {
+		polls++
+		if polls == 12 {
+			cancel()
+		}
+		return nil, errors.New("connection refused")
+	}
+
+	policy := DefaultPolicy()
+	Run(ctx, clock, policy, []ehl.Game{game(ehl.StateLive, start)}, poll)
+
+	for i, wait := range clock.waits[1:] {
+		if wait < policy.MinInterval || wait > policy.Idle {
+			t.Errorf("wait %d = %s, outside [%s, %s]", i, wait, policy.MinInterval, policy.Idle)
+		}
+		if i > 0 && wait < clock.waits[i] {
+			t.Errorf("wait %d = %s shrank from %s", i, wait, clock.waits[i])
+		}
+	}
+	if last := clock.waits[len(clock.waits)-1]; last != policy.Idle {
+		t.Errorf("expected backoff to reach the idle interval, got %s", last)
+	}
+}
+
+func TestRun_StopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	polled := false
+	Run(ctx, RealClock, DefaultPolicy(), nil, func(context.Context) ([]ehl.Game, error) {
+		polled = true
+		return nil, nil
+	})
+
+	if polled {
+		t.Error("expected no poll after cancellation")
+	}
+}